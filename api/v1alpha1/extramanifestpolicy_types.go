@@ -0,0 +1,103 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ExtraManifestPolicyName is the name of the ExtraManifestPolicy the operator evaluates extra manifests against
+	ExtraManifestPolicyName = "cluster"
+
+	ExtraManifestPolicyModeEnforce = "Enforce"
+	ExtraManifestPolicyModeAudit   = "Audit"
+)
+
+// ExtraManifestPolicySpec defines what the extra manifests of ImageClusterInstalls may contain.
+// Kinds and namespaces are matched with shell glob patterns, such as openshift-*.
+type ExtraManifestPolicySpec struct {
+	// Mode is Enforce to fail the ImageClusterInstalls with manifests that violate the policy, or Audit to only
+	// record the violations in their status
+	// +kubebuilder:validation:Enum=Enforce;Audit
+	// +kubebuilder:default:=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// AllowedKinds are the kinds of the objects the manifests may contain, any kind is allowed when empty
+	// +optional
+	AllowedKinds []ManifestKind `json:"allowedKinds,omitempty"`
+
+	// DeniedKinds are the kinds of the objects the manifests may not contain
+	// +optional
+	DeniedKinds []ManifestKind `json:"deniedKinds,omitempty"`
+
+	// AllowedNamespaces are the namespaces of the objects the manifests may contain, any namespace is allowed when
+	// empty. Objects without a namespace aren't checked, Namespace objects are checked by name.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// DeniedNamespaces are the namespaces of the objects the manifests may not contain
+	// +optional
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+
+	// MaxManifestSize is the size limit of each manifest file
+	// +optional
+	MaxManifestSize *resource.Quantity `json:"maxManifestSize,omitempty"`
+}
+
+// ManifestKind matches the group, version and kind of an object
+type ManifestKind struct {
+	// Group is the API group, empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version is the API version, any version matches when empty
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind is the object kind
+	Kind string `json:"kind"`
+}
+
+//+kubebuilder:object:root=true
+// +kubebuilder:resource:path=extramanifestpolicies,scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the ExtraManifestPolicy must be named cluster"
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.mode"
+
+// ExtraManifestPolicy restricts what the extra manifests of all ImageClusterInstalls may contain.
+// Only the ExtraManifestPolicy named cluster is used.
+type ExtraManifestPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ExtraManifestPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ExtraManifestPolicyList contains a list of ExtraManifestPolicy
+type ExtraManifestPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExtraManifestPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExtraManifestPolicy{}, &ExtraManifestPolicyList{})
+}
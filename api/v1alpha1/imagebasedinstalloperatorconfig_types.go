@@ -0,0 +1,203 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apicfgv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	// OperatorConfigName is the name of the ImageBasedInstallOperatorConfig the operator reads its configuration from
+	OperatorConfigName = "cluster"
+
+	// OperatorConfigValidCondition reports whether the operator applies the configuration
+	OperatorConfigValidCondition = "Valid"
+	OperatorConfigValidReason    = "Valid"
+	OperatorConfigInvalidReason  = "Invalid"
+)
+
+// ImageBasedInstallOperatorConfigSpec defines the configuration of the operator.
+// Unset fields keep the values of the operator environment.
+type ImageBasedInstallOperatorConfigSpec struct {
+	// Timeouts are the default timeouts of the installations, ImageClusterInstalls can override them
+	// +optional
+	Timeouts *OperatorTimeouts `json:"timeouts,omitempty"`
+
+	// RequeueIntervals are how often the operator checks on the installations while it waits for them
+	// +optional
+	RequeueIntervals *RequeueIntervals `json:"requeueIntervals,omitempty"`
+
+	// Concurrency limits the work the operator runs in parallel
+	// +optional
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
+
+	// Defaults are set on the ImageClusterInstalls that don't set them
+	// +optional
+	Defaults *InstallDefaults `json:"defaults,omitempty"`
+
+	// ISORetention is how long the configuration image of an installed cluster is kept. The image is kept until the
+	// ImageClusterInstall is deleted when unset.
+	// +optional
+	ISORetention *metav1.Duration `json:"isoRetention,omitempty"`
+
+	// ImageServerURL is the external URL the hosts download the configuration images from. Defaults to the URL of the
+	// operator service.
+	// +optional
+	ImageServerURL string `json:"imageServerURL,omitempty"`
+
+	// RequirePullSecretCoverage fails the requirements of the ImageClusterInstalls whose pull secret has no credentials
+	// for the release image repository or a mirror. The missing credentials are only reported as a warning when unset.
+	// +optional
+	RequirePullSecretCoverage bool `json:"requirePullSecretCoverage,omitempty"`
+}
+
+// OperatorTimeouts defines the default timeouts of the installations
+type OperatorTimeouts struct {
+	// Install is the time a cluster has to finish installing after the host was requested to boot, defaults to 1h
+	// +optional
+	Install *metav1.Duration `json:"install,omitempty"`
+
+	// PhaseTimeouts are the default timeouts of the installation phases, unset phases are only limited by Install
+	PhaseTimeouts `json:",inline"`
+}
+
+// RequeueIntervals defines how often the operator checks on the installations
+type RequeueIntervals struct {
+	// HostValidation is the interval while the host is being provisioned or inspected, defaults to 30s
+	// +optional
+	HostValidation *metav1.Duration `json:"hostValidation,omitempty"`
+
+	// ImageCreation is the interval while the configuration image is being created, defaults to 5s
+	// +optional
+	ImageCreation *metav1.Duration `json:"imageCreation,omitempty"`
+
+	// InstallProgress is the interval of the checks of the installation progress, defaults to 1m
+	// +optional
+	InstallProgress *metav1.Duration `json:"installProgress,omitempty"`
+}
+
+// Concurrency defines the work the operator runs in parallel
+type Concurrency struct {
+	// ImageBuilds is the number of configuration images built in parallel
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ImageBuilds *int32 `json:"imageBuilds,omitempty"`
+}
+
+// InstallDefaults defines the values set on the ImageClusterInstalls that don't set them
+type InstallDefaults struct {
+	// SSHKey is the SSH key set on the ImageClusterInstalls without SSH keys
+	// +optional
+	SSHKey string `json:"sshKey,omitempty"`
+
+	// AdditionalNTPSources are the NTP sources set on the ImageClusterInstalls without NTP sources
+	// +optional
+	AdditionalNTPSources []string `json:"additionalNTPSources,omitempty"`
+
+	// CABundle is the PEM encoded trusted certificates of the ImageClusterInstalls without CA bundle sources
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+
+	// Proxy is the proxy set on the ImageClusterInstalls without a proxy
+	// +optional
+	Proxy *DefaultProxy `json:"proxy,omitempty"`
+
+	// ImageDigestSources are set on the ImageClusterInstalls without image digest sources, and added to the ones of the
+	// ImageClusterInstalls that inherit them
+	// +optional
+	ImageDigestSources []apicfgv1.ImageDigestMirrors `json:"imageDigestSources,omitempty"`
+
+	// UseHubImageDigestMirrorSets adds the mirrors of the hub's ImageDigestMirrorSets to ImageDigestSources
+	// +optional
+	UseHubImageDigestMirrorSets *bool `json:"useHubImageDigestMirrorSets,omitempty"`
+
+	// MergeGlobalPullSecret merges the hub's global pull secret into the pull secret of every ClusterDeployment
+	// +optional
+	MergeGlobalPullSecret *bool `json:"mergeGlobalPullSecret,omitempty"`
+
+	// IPv4MachineNetworkPrefixLength is the prefix length of the IPv4 machine networks derived from the host NIC
+	// addresses, defaults to 24
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=32
+	// +optional
+	IPv4MachineNetworkPrefixLength *int32 `json:"ipv4MachineNetworkPrefixLength,omitempty"`
+
+	// IPv6MachineNetworkPrefixLength is the prefix length of the IPv6 machine networks derived from the host NIC
+	// addresses, defaults to 64
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=128
+	// +optional
+	IPv6MachineNetworkPrefixLength *int32 `json:"ipv6MachineNetworkPrefixLength,omitempty"`
+}
+
+// DefaultProxy defines the default proxy of the ImageClusterInstalls
+type DefaultProxy struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of domains and CIDRs for which the proxy should not be
+	// used.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// ImageBasedInstallOperatorConfigStatus defines the observed state of ImageBasedInstallOperatorConfig
+type ImageBasedInstallOperatorConfigStatus struct {
+	// ObservedGeneration is the generation of the configuration the conditions describe
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the configuration is valid. An invalid configuration is ignored as a whole.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+// +kubebuilder:resource:path=imagebasedinstalloperatorconfigs,scope=Cluster
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the ImageBasedInstallOperatorConfig must be named cluster"
+// +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type=='Valid')].status"
+
+// ImageBasedInstallOperatorConfig configures the operator, changes are applied without restarting it.
+// Only the ImageBasedInstallOperatorConfig named cluster is used.
+type ImageBasedInstallOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageBasedInstallOperatorConfigSpec   `json:"spec,omitempty"`
+	Status ImageBasedInstallOperatorConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ImageBasedInstallOperatorConfigList contains a list of ImageBasedInstallOperatorConfig
+type ImageBasedInstallOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageBasedInstallOperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageBasedInstallOperatorConfig{}, &ImageBasedInstallOperatorConfigList{})
+}
@@ -0,0 +1,431 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/openshift/image-based-install-operator/api/v1beta1"
+)
+
+const (
+	// InstallTimeoutAnnotation overrides the install timeout of an ImageClusterInstall,
+	// it is converted to spec.timeouts.install in v1beta1
+	InstallTimeoutAnnotation = "imageclusterinstall." + Group + "/install-timeout"
+
+	// ImageCreationRetryIntervalAnnotation sets the time to wait before retrying a failed image creation,
+	// it is converted to spec.retryPolicy.imageCreationInterval in v1beta1
+	ImageCreationRetryIntervalAnnotation = "imageclusterinstall." + Group + "/image-creation-retry-interval"
+
+	// conversionDataAnnotation keeps the v1alpha1 values v1beta1 can't represent so that they survive a round-trip
+	conversionDataAnnotation = "imageclusterinstall." + Group + "/v1alpha1-conversion-data"
+
+	sshKeySeparator  = "\n"
+	noProxySeparator = ","
+)
+
+// conversionData holds the v1alpha1 fields removed in v1beta1 and the original form of the fields v1beta1 parses.
+// The removed fields are always restored, the original forms only while they still match the v1beta1 values.
+type conversionData struct {
+	NodeIP                     string `json:"nodeIP,omitempty"`
+	MachineNetwork             string `json:"machineNetwork,omitempty"`
+	MachineNetworkOnly         bool   `json:"machineNetworkOnly,omitempty"`
+	SSHKey                     string `json:"sshKey,omitempty"`
+	NoProxy                    string `json:"noProxy,omitempty"`
+	InstallTimeout             string `json:"installTimeout,omitempty"`
+	ImageCreationRetryInterval string `json:"imageCreationRetryInterval,omitempty"`
+}
+
+var _ conversion.Convertible = &ImageClusterInstall{}
+
+// ConvertTo converts this ImageClusterInstall to the v1beta1 hub version
+func (r *ImageClusterInstall) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.ImageClusterInstall)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *r.ObjectMeta.DeepCopy()
+	delete(dst.Annotations, conversionDataAnnotation)
+
+	spec := r.Spec.DeepCopy()
+	dst.Spec = v1beta1.ImageClusterInstallSpec{
+		ClusterDeploymentRef:        spec.ClusterDeploymentRef,
+		ImageSetRef:                 spec.ImageSetRef,
+		ClusterMetadata:             spec.ClusterMetadata,
+		Hostname:                    spec.Hostname,
+		SSHKeys:                     splitList(spec.SSHKey, sshKeySeparator),
+		SSHKeysSecretRef:            spec.SSHKeysSecretRef,
+		ImageDigestSources:          spec.ImageDigestSources,
+		InheritImageDigestSources:   spec.InheritImageDigestSources,
+		Disconnected:                spec.Disconnected,
+		CABundleRef:                 spec.CABundleRef,
+		CABundleSources:             caBundleSourcesToHub(spec.CABundleSources),
+		ExtraManifestsRefs:          spec.ExtraManifestsRefs,
+		ExtraManifestsSecretRefs:    spec.ExtraManifestsSecretRefs,
+		ExtraManifestsValues:        spec.ExtraManifestsValues,
+		BareMetalHostRef:            (*v1beta1.BareMetalHostReference)(spec.BareMetalHostRef),
+		NetworkConfigRef:            (*v1beta1.NetworkConfigReference)(spec.NetworkConfigRef),
+		MachineNetworks:             machineNetworksToHub(spec.MachineNetworks),
+		AdditionalNTPSources:        spec.AdditionalNTPSources,
+		APIEndpointOverride:         spec.APIEndpointOverride,
+		PostInstallHealthWindow:     spec.PostInstallHealthWindow,
+		PostInstallManifestsRefs:    spec.PostInstallManifestsRefs,
+		ReadinessGates:              readinessGatesToHub(spec.ReadinessGates),
+		WaitForPostInstallManifests: spec.WaitForPostInstallManifests,
+	}
+
+	data := conversionData{NodeIP: spec.NodeIP}
+	if spec.MachineNetwork != "" {
+		data.MachineNetwork = spec.MachineNetwork
+		if len(spec.MachineNetworks) == 0 {
+			dst.Spec.MachineNetworks = []v1beta1.MachineNetworkEntry{{CIDR: spec.MachineNetwork}}
+			data.MachineNetworkOnly = true
+		}
+	}
+	if strings.Join(dst.Spec.SSHKeys, sshKeySeparator) != spec.SSHKey {
+		data.SSHKey = spec.SSHKey
+	}
+	if spec.Proxy != nil {
+		dst.Spec.Proxy = &v1beta1.Proxy{
+			HTTPProxy:  spec.Proxy.HTTPProxy,
+			HTTPSProxy: spec.Proxy.HTTPSProxy,
+			NoProxy:    splitList(spec.Proxy.NoProxy, noProxySeparator),
+			SecretRef:  spec.Proxy.SecretRef,
+		}
+		if strings.Join(dst.Spec.Proxy.NoProxy, noProxySeparator) != spec.Proxy.NoProxy {
+			data.NoProxy = spec.Proxy.NoProxy
+		}
+	}
+
+	if spec.Timeouts != nil {
+		dst.Spec.Timeouts = &v1beta1.Timeouts{
+			PowerOn:             spec.Timeouts.PowerOn,
+			ConfigImageAttached: spec.Timeouts.ConfigImageAttached,
+			SpokeAPIReachable:   spec.Timeouts.SpokeAPIReachable,
+			ClusterConverged:    spec.Timeouts.ClusterConverged,
+		}
+	}
+	if timeout, original := durationFromAnnotation(dst, InstallTimeoutAnnotation); timeout != nil {
+		if dst.Spec.Timeouts == nil {
+			dst.Spec.Timeouts = &v1beta1.Timeouts{}
+		}
+		dst.Spec.Timeouts.Install = timeout
+		data.InstallTimeout = original
+	}
+	if interval, original := durationFromAnnotation(dst, ImageCreationRetryIntervalAnnotation); interval != nil {
+		dst.Spec.RetryPolicy = &v1beta1.RetryPolicy{ImageCreationInterval: interval}
+		data.ImageCreationRetryInterval = original
+	}
+
+	if data != (conversionData{}) {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal conversion data: %w", err)
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[conversionDataAnnotation] = string(raw)
+	}
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	status := r.Status.DeepCopy()
+	dst.Status = v1beta1.ImageClusterInstallStatus{
+		Conditions:                    status.Conditions,
+		InstallRestarts:               status.InstallRestarts,
+		BareMetalHostRef:              (*v1beta1.BareMetalHostReference)(status.BareMetalHostRef),
+		BootTime:                      status.BootTime,
+		ExtraManifestPolicyViolations: status.ExtraManifestPolicyViolations,
+		CACertificates:                caCertificatesToHub(status.CACertificates),
+		LastHealthyTime:               status.LastHealthyTime,
+		PendingReadinessGates:         status.PendingReadinessGates,
+		PostInstallManifests:          postInstallManifestsToHub(status.PostInstallManifests),
+		Diagnostics:                   (*v1beta1.ClusterDiagnostics)(status.Diagnostics),
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub version to this ImageClusterInstall
+func (r *ImageClusterInstall) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.ImageClusterInstall)
+	if !ok {
+		return fmt.Errorf("unsupported conversion hub %T", srcRaw)
+	}
+
+	r.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	data := conversionData{}
+	if raw, present := r.Annotations[conversionDataAnnotation]; present {
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return fmt.Errorf("failed to parse %s annotation: %w", conversionDataAnnotation, err)
+		}
+		delete(r.Annotations, conversionDataAnnotation)
+	}
+
+	spec := src.Spec.DeepCopy()
+	r.Spec = ImageClusterInstallSpec{
+		ClusterDeploymentRef:        spec.ClusterDeploymentRef,
+		ImageSetRef:                 spec.ImageSetRef,
+		ClusterMetadata:             spec.ClusterMetadata,
+		NodeIP:                      data.NodeIP,
+		Hostname:                    spec.Hostname,
+		SSHKey:                      strings.Join(spec.SSHKeys, sshKeySeparator),
+		SSHKeysSecretRef:            spec.SSHKeysSecretRef,
+		ImageDigestSources:          spec.ImageDigestSources,
+		InheritImageDigestSources:   spec.InheritImageDigestSources,
+		Disconnected:                spec.Disconnected,
+		CABundleRef:                 spec.CABundleRef,
+		CABundleSources:             caBundleSourcesFromHub(spec.CABundleSources),
+		ExtraManifestsRefs:          spec.ExtraManifestsRefs,
+		ExtraManifestsSecretRefs:    spec.ExtraManifestsSecretRefs,
+		ExtraManifestsValues:        spec.ExtraManifestsValues,
+		BareMetalHostRef:            (*BareMetalHostReference)(spec.BareMetalHostRef),
+		NetworkConfigRef:            (*NetworkConfigReference)(spec.NetworkConfigRef),
+		MachineNetworks:             machineNetworksFromHub(spec.MachineNetworks),
+		AdditionalNTPSources:        spec.AdditionalNTPSources,
+		APIEndpointOverride:         spec.APIEndpointOverride,
+		PostInstallHealthWindow:     spec.PostInstallHealthWindow,
+		PostInstallManifestsRefs:    spec.PostInstallManifestsRefs,
+		ReadinessGates:              readinessGatesFromHub(spec.ReadinessGates),
+		WaitForPostInstallManifests: spec.WaitForPostInstallManifests,
+	}
+
+	if data.SSHKey != "" && reflect.DeepEqual(splitList(data.SSHKey, sshKeySeparator), spec.SSHKeys) {
+		r.Spec.SSHKey = data.SSHKey
+	}
+	if data.MachineNetwork != "" {
+		r.Spec.MachineNetwork = data.MachineNetwork
+		if data.MachineNetworkOnly && len(spec.MachineNetworks) == 1 && spec.MachineNetworks[0].CIDR == data.MachineNetwork {
+			r.Spec.MachineNetworks = nil
+		}
+	}
+	if spec.Proxy != nil {
+		r.Spec.Proxy = &Proxy{
+			HTTPProxy:  spec.Proxy.HTTPProxy,
+			HTTPSProxy: spec.Proxy.HTTPSProxy,
+			NoProxy:    strings.Join(spec.Proxy.NoProxy, noProxySeparator),
+			SecretRef:  spec.Proxy.SecretRef,
+		}
+		if data.NoProxy != "" && reflect.DeepEqual(splitList(data.NoProxy, noProxySeparator), spec.Proxy.NoProxy) {
+			r.Spec.Proxy.NoProxy = data.NoProxy
+		}
+	}
+
+	if spec.Timeouts != nil {
+		if spec.Timeouts.Install != nil {
+			setDurationAnnotation(r, InstallTimeoutAnnotation, spec.Timeouts.Install, data.InstallTimeout)
+		}
+		phases := PhaseTimeouts{
+			PowerOn:             spec.Timeouts.PowerOn,
+			ConfigImageAttached: spec.Timeouts.ConfigImageAttached,
+			SpokeAPIReachable:   spec.Timeouts.SpokeAPIReachable,
+			ClusterConverged:    spec.Timeouts.ClusterConverged,
+		}
+		if phases != (PhaseTimeouts{}) {
+			r.Spec.Timeouts = &phases
+		}
+	}
+	if spec.RetryPolicy != nil && spec.RetryPolicy.ImageCreationInterval != nil {
+		setDurationAnnotation(r, ImageCreationRetryIntervalAnnotation, spec.RetryPolicy.ImageCreationInterval, data.ImageCreationRetryInterval)
+	}
+	if len(r.Annotations) == 0 {
+		r.Annotations = nil
+	}
+
+	status := src.Status.DeepCopy()
+	r.Status = ImageClusterInstallStatus{
+		Conditions:                    status.Conditions,
+		InstallRestarts:               status.InstallRestarts,
+		BareMetalHostRef:              (*BareMetalHostReference)(status.BareMetalHostRef),
+		BootTime:                      status.BootTime,
+		ExtraManifestPolicyViolations: status.ExtraManifestPolicyViolations,
+		CACertificates:                caCertificatesFromHub(status.CACertificates),
+		LastHealthyTime:               status.LastHealthyTime,
+		PendingReadinessGates:         status.PendingReadinessGates,
+		PostInstallManifests:          postInstallManifestsFromHub(status.PostInstallManifests),
+		Diagnostics:                   (*ClusterDiagnostics)(status.Diagnostics),
+	}
+
+	return nil
+}
+
+// splitList splits a separated list and drops empty entries
+func splitList(list, separator string) []string {
+	var entries []string
+	for _, entry := range strings.Split(list, separator) {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func machineNetworksToHub(networks []MachineNetworkEntry) []v1beta1.MachineNetworkEntry {
+	if networks == nil {
+		return nil
+	}
+	converted := make([]v1beta1.MachineNetworkEntry, len(networks))
+	for i, network := range networks {
+		converted[i] = v1beta1.MachineNetworkEntry(network)
+	}
+	return converted
+}
+
+func machineNetworksFromHub(networks []v1beta1.MachineNetworkEntry) []MachineNetworkEntry {
+	if networks == nil {
+		return nil
+	}
+	converted := make([]MachineNetworkEntry, len(networks))
+	for i, network := range networks {
+		converted[i] = MachineNetworkEntry(network)
+	}
+	return converted
+}
+
+func caBundleSourcesToHub(sources []CABundleSource) []v1beta1.CABundleSource {
+	if sources == nil {
+		return nil
+	}
+	converted := make([]v1beta1.CABundleSource, len(sources))
+	for i, source := range sources {
+		converted[i] = v1beta1.CABundleSource(source)
+	}
+	return converted
+}
+
+func caBundleSourcesFromHub(sources []v1beta1.CABundleSource) []CABundleSource {
+	if sources == nil {
+		return nil
+	}
+	converted := make([]CABundleSource, len(sources))
+	for i, source := range sources {
+		converted[i] = CABundleSource(source)
+	}
+	return converted
+}
+
+func readinessGatesToHub(gates []ReadinessGate) []v1beta1.ReadinessGate {
+	if gates == nil {
+		return nil
+	}
+	converted := make([]v1beta1.ReadinessGate, len(gates))
+	for i, gate := range gates {
+		converted[i] = v1beta1.ReadinessGate(gate)
+	}
+	return converted
+}
+
+func readinessGatesFromHub(gates []v1beta1.ReadinessGate) []ReadinessGate {
+	if gates == nil {
+		return nil
+	}
+	converted := make([]ReadinessGate, len(gates))
+	for i, gate := range gates {
+		converted[i] = ReadinessGate(gate)
+	}
+	return converted
+}
+
+func caCertificatesToHub(certificates []CACertificate) []v1beta1.CACertificate {
+	if certificates == nil {
+		return nil
+	}
+	converted := make([]v1beta1.CACertificate, len(certificates))
+	for i, certificate := range certificates {
+		converted[i] = v1beta1.CACertificate(certificate)
+	}
+	return converted
+}
+
+func caCertificatesFromHub(certificates []v1beta1.CACertificate) []CACertificate {
+	if certificates == nil {
+		return nil
+	}
+	converted := make([]CACertificate, len(certificates))
+	for i, certificate := range certificates {
+		converted[i] = CACertificate(certificate)
+	}
+	return converted
+}
+
+func postInstallManifestsToHub(manifests []PostInstallManifestStatus) []v1beta1.PostInstallManifestStatus {
+	if manifests == nil {
+		return nil
+	}
+	converted := make([]v1beta1.PostInstallManifestStatus, len(manifests))
+	for i, manifest := range manifests {
+		converted[i] = v1beta1.PostInstallManifestStatus(manifest)
+	}
+	return converted
+}
+
+func postInstallManifestsFromHub(manifests []v1beta1.PostInstallManifestStatus) []PostInstallManifestStatus {
+	if manifests == nil {
+		return nil
+	}
+	converted := make([]PostInstallManifestStatus, len(manifests))
+	for i, manifest := range manifests {
+		converted[i] = PostInstallManifestStatus(manifest)
+	}
+	return converted
+}
+
+// durationFromAnnotation moves a duration annotation of obj to the returned duration. The original value is
+// returned when it isn't the canonical form of the duration. Annotations that don't parse are left in place.
+func durationFromAnnotation(obj metav1.Object, annotation string) (*metav1.Duration, string) {
+	value, present := obj.GetAnnotations()[annotation]
+	if !present {
+		return nil, ""
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, ""
+	}
+
+	annotations := obj.GetAnnotations()
+	delete(annotations, annotation)
+	obj.SetAnnotations(annotations)
+	if duration.String() == value {
+		return &metav1.Duration{Duration: duration}, ""
+	}
+	return &metav1.Duration{Duration: duration}, value
+}
+
+// setDurationAnnotation sets a duration annotation of obj, using the original value while it still matches duration
+func setDurationAnnotation(obj metav1.Object, annotation string, duration *metav1.Duration, original string) {
+	value := duration.Duration.String()
+	if parsed, err := time.ParseDuration(original); err == nil && parsed == duration.Duration {
+		value = original
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[annotation] = value
+	obj.SetAnnotations(annotations)
+}
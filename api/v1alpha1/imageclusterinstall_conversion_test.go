@@ -0,0 +1,200 @@
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+
+	"github.com/openshift/image-based-install-operator/api/v1beta1"
+)
+
+var _ = Describe("ImageClusterInstall conversion", func() {
+	roundTrip := func(ici *ImageClusterInstall) *ImageClusterInstall {
+		hub := &v1beta1.ImageClusterInstall{}
+		Expect(ici.ConvertTo(hub)).To(Succeed())
+		converted := &ImageClusterInstall{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		return converted
+	}
+
+	fullClusterInstall := func() *ImageClusterInstall {
+		return &ImageClusterInstall{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ici",
+				Namespace:   "test-namespace",
+				Labels:      map[string]string{"app": "test"},
+				Annotations: map[string]string{"other": "value"},
+			},
+			Spec: ImageClusterInstallSpec{
+				ClusterDeploymentRef:      &corev1.LocalObjectReference{Name: "cd"},
+				ImageSetRef:               hivev1.ClusterImageSetReference{Name: "imageset"},
+				ClusterMetadata:           &hivev1.ClusterMetadata{ClusterID: "id", InfraID: "infra"},
+				NodeIP:                    "192.0.2.10",
+				Hostname:                  "host",
+				SSHKey:                    "ssh-rsa AAAA one\nssh-ed25519 AAAA two",
+				SSHKeysSecretRef:          &corev1.LocalObjectReference{Name: "ssh-keys"},
+				InheritImageDigestSources: true,
+				Disconnected:              true,
+				CABundleRef:               &corev1.LocalObjectReference{Name: "ca"},
+				CABundleSources:           []CABundleSource{{Kind: "Secret", Name: "site-ca", Key: "ca.crt"}},
+				ExtraManifestsRefs:        []corev1.LocalObjectReference{{Name: "manifests"}},
+				ExtraManifestsSecretRefs:  []corev1.LocalObjectReference{{Name: "secret-manifests"}},
+				ExtraManifestsValues:      map[string]string{"site": "one"},
+				BareMetalHostRef:          &BareMetalHostReference{Name: "bmh", Namespace: "bmh-ns"},
+				NetworkConfigRef:          &NetworkConfigReference{Kind: NetworkConfigKindConfigMap, Name: "nmstate"},
+				MachineNetwork:            "192.0.2.0/24",
+				MachineNetworks:           []MachineNetworkEntry{{CIDR: "192.0.2.0/24"}, {CIDR: "2001:db8::/64"}},
+				Proxy: &Proxy{
+					HTTPProxy: "http://proxy.example.com:3128",
+					NoProxy:   "example.com,192.0.2.0/24",
+					SecretRef: &corev1.LocalObjectReference{Name: "proxy"},
+				},
+				AdditionalNTPSources:     []string{"ntp.example.com"},
+				APIEndpointOverride:      "192.0.2.10:6443",
+				PostInstallHealthWindow:  &metav1.Duration{Duration: 4 * time.Hour},
+				PostInstallManifestsRefs: []corev1.LocalObjectReference{{Name: "day2"}},
+				ReadinessGates: []ReadinessGate{{
+					APIVersion:    "apps/v1",
+					Kind:          "Deployment",
+					Namespace:     "openshift-ptp",
+					Name:          "ptp-operator",
+					ConditionType: "Available",
+				}},
+				Timeouts:                    &PhaseTimeouts{PowerOn: &metav1.Duration{Duration: 10 * time.Minute}},
+				WaitForPostInstallManifests: true,
+			},
+			Status: ImageClusterInstallStatus{
+				Conditions:                    []hivev1.ClusterInstallCondition{{Type: hivev1.ClusterInstallCompleted, Status: corev1.ConditionTrue}},
+				InstallRestarts:               1,
+				BareMetalHostRef:              &BareMetalHostReference{Name: "bmh", Namespace: "bmh-ns"},
+				BootTime:                      metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+				ExtraManifestPolicyViolations: []string{"violation"},
+				CACertificates: []CACertificate{{
+					Fingerprint: "AB:CD",
+					Subject:     "CN=site",
+					NotAfter:    metav1.NewTime(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)),
+				}},
+				LastHealthyTime:       &metav1.Time{Time: time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC)},
+				PendingReadinessGates: []string{"Deployment openshift-ptp/ptp-operator: not found"},
+				PostInstallManifests: []PostInstallManifestStatus{{
+					APIVersion:      "operators.coreos.com/v1alpha1",
+					Kind:            "Subscription",
+					Namespace:       "openshift-ptp",
+					Name:            "ptp-operator",
+					State:           PostInstallManifestFailed,
+					Message:         "no matches for kind",
+					Attempts:        2,
+					LastAttemptTime: &metav1.Time{Time: time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)},
+				}},
+				Diagnostics: &ClusterDiagnostics{
+					ConfigMapRef:   corev1.LocalObjectReference{Name: "ici-diagnostics"},
+					Reason:         ClusterConvergenceTimedoutReason,
+					CollectionTime: metav1.NewTime(time.Date(2024, 1, 1, 3, 0, 0, 0, time.UTC)),
+					Truncated:      true,
+				},
+			},
+		}
+	}
+
+	It("converts to structured v1beta1 fields", func() {
+		ici := fullClusterInstall()
+		ici.Annotations[InstallTimeoutAnnotation] = "2h0m0s"
+		ici.Annotations[ImageCreationRetryIntervalAnnotation] = "30s"
+
+		hub := &v1beta1.ImageClusterInstall{}
+		Expect(ici.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.SSHKeys).To(Equal([]string{"ssh-rsa AAAA one", "ssh-ed25519 AAAA two"}))
+		Expect(hub.Spec.Proxy.NoProxy).To(Equal([]string{"example.com", "192.0.2.0/24"}))
+		Expect(hub.Spec.Proxy.SecretRef).To(Equal(&corev1.LocalObjectReference{Name: "proxy"}))
+		Expect(hub.Spec.SSHKeysSecretRef).To(Equal(&corev1.LocalObjectReference{Name: "ssh-keys"}))
+		Expect(hub.Spec.MachineNetworks).To(Equal([]v1beta1.MachineNetworkEntry{{CIDR: "192.0.2.0/24"}, {CIDR: "2001:db8::/64"}}))
+		Expect(hub.Spec.NetworkConfigRef).To(Equal(&v1beta1.NetworkConfigReference{Kind: "ConfigMap", Name: "nmstate"}))
+		Expect(hub.Spec.Timeouts.Install.Duration).To(Equal(2 * time.Hour))
+		Expect(hub.Spec.Timeouts.PowerOn.Duration).To(Equal(10 * time.Minute))
+		Expect(hub.Spec.RetryPolicy.ImageCreationInterval.Duration).To(Equal(30 * time.Second))
+		Expect(hub.Annotations).NotTo(HaveKey(InstallTimeoutAnnotation))
+		Expect(hub.Annotations).NotTo(HaveKey(ImageCreationRetryIntervalAnnotation))
+		Expect(hub.Annotations).To(HaveKeyWithValue("other", "value"))
+		Expect(hub.Status.BareMetalHostRef).To(Equal(&v1beta1.BareMetalHostReference{Name: "bmh", Namespace: "bmh-ns"}))
+	})
+
+	It("moves a lone machineNetwork into machineNetworks", func() {
+		ici := fullClusterInstall()
+		ici.Spec.MachineNetworks = nil
+
+		hub := &v1beta1.ImageClusterInstall{}
+		Expect(ici.ConvertTo(hub)).To(Succeed())
+		Expect(hub.Spec.MachineNetworks).To(Equal([]v1beta1.MachineNetworkEntry{{CIDR: "192.0.2.0/24"}}))
+	})
+
+	It("round-trips v1alpha1 objects losslessly", func() {
+		ici := fullClusterInstall()
+		Expect(roundTrip(ici)).To(Equal(ici))
+
+		ici.Spec.MachineNetworks = nil
+		ici.Spec.SSHKey = "  ssh-rsa AAAA one\n\n"
+		ici.Spec.Proxy.NoProxy = "example.com, .example.org"
+		ici.Annotations[InstallTimeoutAnnotation] = "90m"
+		ici.Annotations[ImageCreationRetryIntervalAnnotation] = "not-a-duration"
+		Expect(roundTrip(ici)).To(Equal(ici))
+
+		ici.Spec.MachineNetwork = "198.51.100.0/24"
+		ici.Spec.MachineNetworks = []MachineNetworkEntry{{CIDR: "192.0.2.0/24"}}
+		Expect(roundTrip(ici)).To(Equal(ici))
+	})
+
+	It("round-trips v1alpha1 objects without optional fields", func() {
+		ici := &ImageClusterInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "ici", Namespace: "test-namespace"},
+			Spec:       ImageClusterInstallSpec{ImageSetRef: hivev1.ClusterImageSetReference{Name: "imageset"}},
+		}
+		Expect(roundTrip(ici)).To(Equal(ici))
+	})
+
+	It("round-trips v1beta1 objects losslessly", func() {
+		hub := &v1beta1.ImageClusterInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "ici", Namespace: "test-namespace"},
+			Spec: v1beta1.ImageClusterInstallSpec{
+				ImageSetRef:     hivev1.ClusterImageSetReference{Name: "imageset"},
+				SSHKeys:         []string{"ssh-rsa AAAA one", "ssh-ed25519 AAAA two"},
+				MachineNetworks: []v1beta1.MachineNetworkEntry{{CIDR: "192.0.2.0/24"}},
+				Proxy:           &v1beta1.Proxy{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: []string{"example.com"}},
+				Timeouts: &v1beta1.Timeouts{
+					Install:          &metav1.Duration{Duration: 3 * time.Hour},
+					ClusterConverged: &metav1.Duration{Duration: 2 * time.Hour},
+				},
+				RetryPolicy: &v1beta1.RetryPolicy{ImageCreationInterval: &metav1.Duration{Duration: time.Minute}},
+			},
+		}
+
+		ici := &ImageClusterInstall{}
+		Expect(ici.ConvertFrom(hub)).To(Succeed())
+		Expect(ici.Spec.SSHKey).To(Equal("ssh-rsa AAAA one\nssh-ed25519 AAAA two"))
+		Expect(ici.Spec.Proxy.NoProxy).To(Equal("example.com"))
+		Expect(ici.Annotations).To(HaveKeyWithValue(InstallTimeoutAnnotation, "3h0m0s"))
+		Expect(ici.Spec.Timeouts.ClusterConverged.Duration).To(Equal(2 * time.Hour))
+
+		converted := &v1beta1.ImageClusterInstall{}
+		Expect(ici.ConvertTo(converted)).To(Succeed())
+		Expect(converted).To(Equal(hub))
+	})
+
+	It("drops original values that no longer match the v1beta1 fields", func() {
+		ici := fullClusterInstall()
+		hub := &v1beta1.ImageClusterInstall{}
+		Expect(ici.ConvertTo(hub)).To(Succeed())
+
+		hub.Spec.SSHKeys = []string{"ssh-rsa AAAA three"}
+
+		converted := &ImageClusterInstall{}
+		Expect(converted.ConvertFrom(hub)).To(Succeed())
+		Expect(converted.Spec.SSHKey).To(Equal("ssh-rsa AAAA three"))
+		Expect(converted.Spec.NodeIP).To(Equal("192.0.2.10"))
+		Expect(converted.Annotations).NotTo(HaveKey(conversionDataAnnotation))
+	})
+})
@@ -25,8 +25,12 @@ import (
 )
 
 const (
-	ConfigurationPendingReason = "ConfigurationPending"
-	ConfigurationFailedReason  = "ConfigurationFailed"
+	ConfigurationPendingReason  = "ConfigurationPending"
+	ConfigurationFailedReason   = "ConfigurationFailed"
+	PreviewRenderedReason       = "PreviewRendered"
+	ExtraManifestsInvalidReason = "ExtraManifestsInvalid"
+	CABundleInvalidReason       = "CABundleInvalid"
+	PullSecretInvalidReason     = "PullSecretInvalid"
 
 	ImageCreationFailedReason  = "ImageCreationFailed"
 	ImageCreationPendingReason = "ImageCreationPending"
@@ -38,6 +42,11 @@ const (
 	InstallTimedoutReason  = "ClusterInstallationTimedOut"
 	InstallTimedoutMessage = "Cluster installation is taking longer than expected"
 
+	HostPowerOnTimedoutReason        = "HostPowerOnTimedOut"
+	ConfigImageAttachTimedoutReason  = "ConfigImageAttachTimedOut"
+	SpokeAPIReachableTimedoutReason  = "SpokeAPIReachableTimedOut"
+	ClusterConvergenceTimedoutReason = "ClusterConvergenceTimedOut"
+
 	InstallInProgressReason  = "ClusterInstallationInProgress"
 	InstallInProgressMessage = "Cluster installation is in progress"
 
@@ -48,6 +57,16 @@ const (
 	HostValidationPendingReason = "HostValidationPending"
 )
 
+// ClusterHealthyCondition reports the health of an installed cluster during its post-install health window
+const ClusterHealthyCondition hivev1.ClusterInstallConditionType = "ClusterHealthy"
+
+const (
+	ClusterHealthyReason     = "ClusterHealthy"
+	ClusterUnhealthyReason   = "ClusterUnhealthy"
+	ClusterUnreachableReason = "ClusterUnreachable"
+	HealthWatchEndedReason   = "HealthWatchEnded"
+)
+
 // ImageClusterInstallSpec defines the desired state of ImageClusterInstall
 type ImageClusterInstallSpec struct {
 	// ClusterDeploymentRef is a reference to the ClusterDeployment.
@@ -75,22 +94,57 @@ type ImageClusterInstallSpec struct {
 	// This key will be added to the host to allow ssh access
 	SSHKey string `json:"sshKey,omitempty"`
 
+	// SSHKeysSecretRef references a Secret in the ImageClusterInstall namespace with one or more SSH authorized
+	// keys, one per line, under the authorized_keys key. They are added to SSHKey.
+	// +optional
+	SSHKeysSecretRef *corev1.LocalObjectReference `json:"sshKeysSecretRef,omitempty"`
+
 	// ImageDigestSources lists sources/repositories for the release-image content.
 	// +optional
 	ImageDigestSources []apicfgv1.ImageDigestMirrors `json:"imageDigestSources,omitempty"`
 
+	// InheritImageDigestSources adds the hub-wide image digest sources to ImageDigestSources. Without it the hub-wide
+	// sources are only used when ImageDigestSources is empty.
+	// +optional
+	InheritImageDigestSources bool `json:"inheritImageDigestSources,omitempty"`
+
+	// Disconnected marks a host that can only pull images from mirrors. The repository of the release image must be
+	// a source with mirrors in ImageDigestSources.
+	// +optional
+	Disconnected bool `json:"disconnected,omitempty"`
+
 	// CABundle is a reference to a config map containing the new bundle of trusted certificates for the host.
 	// The tls-ca-bundle.pem entry in the config map will be written to /etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem
 	CABundleRef *corev1.LocalObjectReference `json:"caBundleRef,omitempty"`
 
+	// CABundleSources are more ConfigMaps and Secrets containing trusted certificates for the host. Their
+	// certificates are merged with the ones of CABundleRef, duplicates are removed.
+	// +optional
+	CABundleSources []CABundleSource `json:"caBundleSources,omitempty"`
+
 	// ExtraManifestsRefs is list of config map references containing additional manifests to be applied to the relocated cluster.
 	// +optional
 	ExtraManifestsRefs []corev1.LocalObjectReference `json:"extraManifestsRefs,omitempty"`
 
+	// ExtraManifestsSecretRefs is list of secret references containing additional manifests to be applied to the
+	// relocated cluster. Use it for manifests with sensitive data.
+	// +optional
+	ExtraManifestsSecretRefs []corev1.LocalObjectReference `json:"extraManifestsSecretRefs,omitempty"`
+
+	// ExtraManifestsValues are the values available to the templated extra manifests as .Values
+	// +optional
+	ExtraManifestsValues map[string]string `json:"extraManifestsValues,omitempty"`
+
 	// BareMetalHostRef identifies a BareMetalHost object to be used to attach the configuration to the host.
 	// +optional
 	BareMetalHostRef *BareMetalHostReference `json:"bareMetalHostRef,omitempty"`
 
+	// NetworkConfigRef references a Secret or ConfigMap in the ImageClusterInstall namespace with the nmstate
+	// network configuration of the host under the nmstate key.
+	// It takes precedence over the preprovisioning network data of the BareMetalHost.
+	// +optional
+	NetworkConfigRef *NetworkConfigReference `json:"networkConfigRef,omitempty"`
+
 	// MachineNetwork is the subnet provided by user for the ocp cluster.
 	// This will be used to create the node network and choose ip address for the node.
 	// Equivalent to install-config.yaml's machineNetwork.
@@ -114,6 +168,92 @@ type ImageClusterInstallSpec struct {
 	// hosts. They are added to any NTP sources that were configured through other means.
 	// +optional
 	AdditionalNTPSources []string `json:"additionalNTPSources,omitempty"`
+
+	// APIEndpointOverride is the address the operator uses to reach the API of the installed cluster instead of the
+	// server of its admin kubeconfig, as an IP or host with an optional port, e.g. 192.0.2.10:6443. Use it when the hub
+	// can't resolve the DNS name of the cluster. The certificate of the API server is still validated against the host
+	// of the admin kubeconfig server. It can be changed after the installation started.
+	// +optional
+	APIEndpointOverride string `json:"apiEndpointOverride,omitempty"`
+
+	// PostInstallHealthWindow is how long the health of the cluster is watched after it was installed. The health is
+	// reported by the ClusterHealthy condition and doesn't change the install conditions.
+	// +optional
+	PostInstallHealthWindow *metav1.Duration `json:"postInstallHealthWindow,omitempty"`
+
+	// PostInstallManifestsRefs are ConfigMaps with manifests applied in order to the installed cluster with
+	// server-side apply once it is installed. Use them for objects that can't be applied during the reconfiguration,
+	// such as OLM Subscriptions and the custom resources of the operators they install. They aren't templated.
+	// +optional
+	PostInstallManifestsRefs []corev1.LocalObjectReference `json:"postInstallManifestsRefs,omitempty"`
+
+	// ReadinessGates must all pass, in addition to the cluster version and the nodes being ready, before the
+	// installation is reported as completed. They are evaluated on the installed cluster.
+	// +optional
+	ReadinessGates []ReadinessGate `json:"readinessGates,omitempty"`
+
+	// WaitForPostInstallManifests keeps the installation in progress until all the post-install manifests are applied
+	// +optional
+	WaitForPostInstallManifests bool `json:"waitForPostInstallManifests,omitempty"`
+
+	// Timeouts overrides the operator timeouts of the installation phases. The install timeout is set with the
+	// install-timeout annotation.
+	// +optional
+	Timeouts *PhaseTimeouts `json:"timeouts,omitempty"`
+}
+
+// PhaseTimeouts defines the time the installation phases have to complete, measured from the time the host was
+// requested to boot. A phase without a timeout is only limited by the install timeout.
+type PhaseTimeouts struct {
+	// PowerOn is the time the host has to power on
+	// +optional
+	PowerOn *metav1.Duration `json:"powerOn,omitempty"`
+
+	// ConfigImageAttached is the time the configuration image has to be attached to the host
+	// +optional
+	ConfigImageAttached *metav1.Duration `json:"configImageAttached,omitempty"`
+
+	// SpokeAPIReachable is the time the API of the installed cluster has to answer
+	// +optional
+	SpokeAPIReachable *metav1.Duration `json:"spokeAPIReachable,omitempty"`
+
+	// ClusterConverged is the time the cluster version and the nodes of the installed cluster have to be ready
+	// +optional
+	ClusterConverged *metav1.Duration `json:"clusterConverged,omitempty"`
+}
+
+// ReadinessGate is a condition on an object of the installed cluster that must pass before the installation is
+// reported as completed. Exactly one of ConditionType and JSONPath must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.conditionType) != has(self.jsonPath)",message="exactly one of conditionType and jsonPath must be set"
+type ReadinessGate struct {
+	// APIVersion is the group and version of the object, e.g. apps/v1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the object
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, unset for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object
+	Name string `json:"name"`
+
+	// ConditionType is the type of the condition in the status of the object that must have ConditionStatus
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// ConditionStatus is the expected status of the condition, defaults to True
+	// +optional
+	ConditionStatus string `json:"conditionStatus,omitempty"`
+
+	// JSONPath is a JSONPath expression evaluated on the object, e.g. {.status.syncStatus}
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Value is the expected result of JSONPath
+	// +optional
+	Value string `json:"value,omitempty"`
 }
 
 // ImageClusterInstallStatus defines the observed state of ImageClusterInstall
@@ -129,6 +269,74 @@ type ImageClusterInstallStatus struct {
 
 	// BootTime indicates the time at which the host was requested to boot. Used to determine install timeouts.
 	BootTime metav1.Time `json:"bootTime,omitempty"`
+
+	// ExtraManifestPolicyViolations are the violations of the ExtraManifestPolicy by the extra manifests
+	// +optional
+	ExtraManifestPolicyViolations []string `json:"extraManifestPolicyViolations,omitempty"`
+
+	// CACertificates are the trusted certificates included in the configuration image
+	// +optional
+	CACertificates []CACertificate `json:"caCertificates,omitempty"`
+
+	// LastHealthyTime is the last time the installed cluster was seen healthy during the post-install health window
+	// +optional
+	LastHealthyTime *metav1.Time `json:"lastHealthyTime,omitempty"`
+
+	// PendingReadinessGates are the readiness gates that don't pass yet and why
+	// +optional
+	PendingReadinessGates []string `json:"pendingReadinessGates,omitempty"`
+
+	// PostInstallManifests are the results of applying the post-install manifests to the installed cluster
+	// +optional
+	PostInstallManifests []PostInstallManifestStatus `json:"postInstallManifests,omitempty"`
+
+	// Diagnostics references the diagnostics collected from the cluster when the installation failed
+	// +optional
+	Diagnostics *ClusterDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// ClusterDiagnostics references the diagnostics collected from the cluster when the installation failed
+type ClusterDiagnostics struct {
+	// ConfigMapRef references the ConfigMap in the ImageClusterInstall namespace that holds the diagnostics
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
+	// Reason is the reason of the failure the diagnostics were collected for
+	Reason string `json:"reason"`
+	// CollectionTime is the time the diagnostics were collected
+	CollectionTime metav1.Time `json:"collectionTime"`
+	// Truncated is true when some of the diagnostics were truncated to fit in the ConfigMap
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+const (
+	PostInstallManifestPending = "Pending"
+	PostInstallManifestApplied = "Applied"
+	PostInstallManifestFailed  = "Failed"
+)
+
+// PostInstallManifestStatus is the result of applying a post-install manifest to the installed cluster
+type PostInstallManifestStatus struct {
+	// APIVersion is the group and version of the object
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object, unset for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// State is Pending, Applied or Failed
+	// +kubebuilder:validation:Enum=Pending;Applied;Failed
+	State string `json:"state"`
+	// Message is the error of the last failed attempt
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Attempts is the number of times the object was applied
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastAttemptTime is the last time the object was applied
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
 }
 
 type BareMetalHostReference struct {
@@ -138,6 +346,51 @@ type BareMetalHostReference struct {
 	Namespace string `json:"namespace"`
 }
 
+const (
+	NetworkConfigKindSecret    = "Secret"
+	NetworkConfigKindConfigMap = "ConfigMap"
+)
+
+const (
+	CABundleSourceKindConfigMap = "ConfigMap"
+	CABundleSourceKindSecret    = "Secret"
+)
+
+// CABundleSource references a ConfigMap or Secret key containing PEM encoded certificates
+type CABundleSource struct {
+	// Kind is the kind of the referenced object, ConfigMap or Secret
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:default:=ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name identifies the referenced object within the ImageClusterInstall namespace
+	Name string `json:"name"`
+	// Key is the key of the certificates in the referenced object, tls-ca-bundle.pem when empty
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CACertificate identifies a trusted certificate
+type CACertificate struct {
+	// Fingerprint is the SHA-256 fingerprint of the certificate
+	Fingerprint string `json:"fingerprint"`
+	// Subject is the subject of the certificate
+	Subject string `json:"subject"`
+	// NotAfter is the time the certificate expires
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// NetworkConfigReference references an object containing an nmstate network configuration
+type NetworkConfigReference struct {
+	// Kind is the kind of the referenced object, Secret or ConfigMap
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default:=Secret
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name identifies the referenced object within the ImageClusterInstall namespace
+	Name string `json:"name"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 // +kubebuilder:resource:path=imageclusterinstalls,shortName=ici
@@ -155,7 +408,7 @@ type ImageClusterInstall struct {
 }
 
 // Proxy defines the proxy settings for the cluster.
-// At least one of HTTPProxy or HTTPSProxy is required.
+// At least one of HTTPProxy, HTTPSProxy or SecretRef is required.
 type Proxy struct {
 	// HTTPProxy is the URL of the proxy for HTTP requests.
 	// +optional
@@ -169,6 +422,12 @@ type Proxy struct {
 	// used.
 	// +optional
 	NoProxy string `json:"noProxy,omitempty"`
+
+	// SecretRef references a Secret in the ImageClusterInstall namespace with the proxy URLs under the httpProxy and
+	// httpsProxy keys. Use it for URLs that contain credentials, its values take precedence over HTTPProxy and
+	// HTTPSProxy.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
 }
 
 // MachineNetworkEntry is a single IP address block for node IP blocks.
@@ -17,17 +17,34 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"reflect"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/go-logr/logr"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"golang.org/x/crypto/ssh"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/jsonpath"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -36,46 +53,552 @@ import (
 // log is for logging in this package.
 var icilog = logf.Log.WithName("imageclusterinstall-resource")
 
-func (r *ImageClusterInstall) SetupWebhookWithManager(mgr ctrl.Manager) error {
+const (
+	// BareMetalHostRefNameIndex and BareMetalHostRefNamespaceIndex are the field indexes of ImageClusterInstalls by
+	// their spec.bareMetalHostRef, they are registered by the ImageClusterInstall controller
+	BareMetalHostRefNameIndex      = ".spec.bareMetalHostRef.name"
+	BareMetalHostRefNamespaceIndex = ".spec.bareMetalHostRef.namespace"
+
+	// CABundleKey is the key of the CA bundle in the ConfigMap referenced by spec.caBundleRef
+	CABundleKey = "tls-ca-bundle.pem"
+
+	// SSHKeysSecretKey is the key of the authorized keys in the Secret referenced by spec.sshKeysSecretRef
+	SSHKeysSecretKey = "authorized_keys"
+
+	// HTTPProxySecretKey and HTTPSProxySecretKey are the keys of the proxy URLs in the Secret referenced by
+	// spec.proxy.secretRef
+	HTTPProxySecretKey  = "httpProxy"
+	HTTPSProxySecretKey = "httpsProxy"
+
+	// ExtraManifestsTemplateAnnotation set to true on an extra manifests ConfigMap renders its manifests as Go
+	// templates before they are validated
+	ExtraManifestsTemplateAnnotation = "extramanifests." + Group + "/template"
+)
+
+// SetupWebhookWithManager registers the ImageClusterInstall webhooks.
+// With strictValidation problems found in the objects referenced by an ImageClusterInstall reject it
+// instead of being returned as warnings.
+func (r *ImageClusterInstall) SetupWebhookWithManager(mgr ctrl.Manager, strictValidation bool) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(&imageClusterInstallDefaulter{}).
+		WithValidator(&imageClusterInstallValidator{
+			Reader:       mgr.GetClient(),
+			secretReader: mgr.GetAPIReader(),
+			strict:       strictValidation,
+		}).
 		Complete()
 }
 
+// imageClusterInstallValidator runs the ImageClusterInstall validations, checks that the objects it claims
+// are not already claimed by another ImageClusterInstall and validates the objects it references
+type imageClusterInstallValidator struct {
+	client.Reader
+	// secretReader reads referenced Secrets, the manager cache only holds the Secrets created by the operator
+	secretReader client.Reader
+	strict       bool
+}
+
+var _ admission.CustomValidator = &imageClusterInstallValidator{}
+
+func (v *imageClusterInstallValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ici, ok := obj.(*ImageClusterInstall)
+	if !ok {
+		return nil, fmt.Errorf("object is not an ImageClusterInstall")
+	}
+
+	return v.validateCreate(ctx, ici, true)
+}
+
+// validateCreate runs the validations of a new ImageClusterInstall, with checkClaims the objects it claims are
+// checked before the objects it references
+func (v *imageClusterInstallValidator) validateCreate(ctx context.Context, ici *ImageClusterInstall, checkClaims bool) (admission.Warnings, error) {
+	warnings, err := ici.ValidateCreate()
+	if err != nil {
+		return warnings, err
+	}
+
+	if checkClaims {
+		claimWarnings, err := v.validateClaims(ctx, ici, nil)
+		warnings = append(warnings, claimWarnings...)
+		if err != nil {
+			return warnings, err
+		}
+	}
+
+	refWarnings, err := v.validateReferences(ctx, ici)
+	return append(warnings, refWarnings...), err
+}
+
+func (v *imageClusterInstallValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	ici, ok := newObj.(*ImageClusterInstall)
+	if !ok {
+		return nil, fmt.Errorf("object is not an ImageClusterInstall")
+	}
+
+	warnings, err := ici.ValidateUpdate(oldObj)
+	if err != nil {
+		return warnings, err
+	}
+
+	// ValidateUpdate already checked the type of oldObj
+	oldClusterInstall := oldObj.(*ImageClusterInstall)
+	claimWarnings, err := v.validateClaims(ctx, ici, oldClusterInstall)
+	if err != nil || !ici.DeletionTimestamp.IsZero() || !isSpecUpdate(oldClusterInstall, ici) {
+		return append(warnings, claimWarnings...), err
+	}
+
+	refWarnings, err := v.validateReferences(ctx, ici)
+	return append(append(warnings, claimWarnings...), refWarnings...), err
+}
+
+func (v *imageClusterInstallValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ici, ok := obj.(*ImageClusterInstall)
+	if !ok {
+		return nil, fmt.Errorf("object is not an ImageClusterInstall")
+	}
+	return ici.ValidateDelete()
+}
+
+// imageClusterInstallDefaulter migrates deprecated ImageClusterInstall fields to their replacements
+type imageClusterInstallDefaulter struct{}
+
+var _ admission.CustomDefaulter = &imageClusterInstallDefaulter{}
+
+func (d *imageClusterInstallDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	ici, ok := obj.(*ImageClusterInstall)
+	if !ok {
+		return fmt.Errorf("object is not an ImageClusterInstall")
+	}
+	// don't block finalizer removal of objects created before the migration
+	if !ici.DeletionTimestamp.IsZero() {
+		return nil
+	}
+	return DefaultImageClusterInstall(ici)
+}
+
+// DefaultImageClusterInstall sets the defaults of the webhook on ici
+func DefaultImageClusterInstall(ici *ImageClusterInstall) error {
+	return migrateMachineNetwork(&ici.Spec)
+}
+
+// ValidateImageClusterInstall runs the validations of the webhook on the creation of ici, the objects it references
+// are read from reader. With strict, problems found in the referenced objects are returned as an error instead of
+// warnings. The objects ici claims are only checked by the webhook, against the other ImageClusterInstalls of the hub.
+func ValidateImageClusterInstall(ctx context.Context, reader client.Reader, ici *ImageClusterInstall, strict bool) (admission.Warnings, error) {
+	v := &imageClusterInstallValidator{Reader: reader, strict: strict}
+	return v.validateCreate(ctx, ici, false)
+}
+
+// migrateMachineNetwork moves the deprecated machineNetwork into machineNetworks when it is the only one set
+// and drops it when it matches the first machineNetworks entry
+func migrateMachineNetwork(spec *ImageClusterInstallSpec) error {
+	if spec.MachineNetwork == "" {
+		return nil
+	}
+	if err := isMatchingMachineNetwork(spec.MachineNetwork, spec.MachineNetworks); err != nil {
+		return err
+	}
+	if len(spec.MachineNetworks) == 0 {
+		spec.MachineNetworks = []MachineNetworkEntry{{CIDR: spec.MachineNetwork}}
+	}
+	spec.MachineNetwork = ""
+	return nil
+}
+
+// validateClaims rejects an ImageClusterInstall that references a BareMetalHost, or a cluster name and base domain,
+// already used by another ImageClusterInstall that has not completed, and warns when the ClusterDeployment is shared.
+// Claims are only checked when they change so that existing conflicts don't block updates such as finalizer removal.
+func (v *imageClusterInstallValidator) validateClaims(ctx context.Context, ici, old *ImageClusterInstall) (admission.Warnings, error) {
+	if !ici.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	if ref := ici.Spec.BareMetalHostRef; ref != nil && ref.Name != "" &&
+		(old == nil || old.Spec.BareMetalHostRef == nil || *old.Spec.BareMetalHostRef != *ref) {
+		claimedBy, err := v.bareMetalHostClaim(ctx, ici)
+		if err != nil {
+			return nil, err
+		}
+		if claimedBy != nil {
+			return nil, fmt.Errorf("BareMetalHost %s/%s is already referenced by ImageClusterInstall %s/%s",
+				ref.Namespace, ref.Name, claimedBy.Namespace, claimedBy.Name)
+		}
+	}
+
+	if ref := ici.Spec.ClusterDeploymentRef; ref != nil && ref.Name != "" &&
+		(old == nil || old.Spec.ClusterDeploymentRef == nil || old.Spec.ClusterDeploymentRef.Name != ref.Name) {
+		sharedWith, err := v.clusterDeploymentUsers(ctx, ici)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range sharedWith {
+			warnings = append(warnings, fmt.Sprintf("ClusterDeployment %s/%s is also referenced by ImageClusterInstall %s",
+				ici.Namespace, ref.Name, other))
+		}
+
+		claimedBy, err := v.clusterIdentityClaim(ctx, ici)
+		if err != nil {
+			return nil, err
+		}
+		if claimedBy != nil {
+			return nil, fmt.Errorf("cluster name and base domain of ClusterDeployment %s/%s are already used by ImageClusterInstall %s/%s",
+				ici.Namespace, ref.Name, claimedBy.Namespace, claimedBy.Name)
+		}
+	}
+
+	return warnings, nil
+}
+
+// bareMetalHostClaim returns the ImageClusterInstall that has not completed and references the same BareMetalHost as ici
+func (v *imageClusterInstallValidator) bareMetalHostClaim(ctx context.Context, ici *ImageClusterInstall) (*ImageClusterInstall, error) {
+	ref := ici.Spec.BareMetalHostRef
+	iciList := &ImageClusterInstallList{}
+	if err := v.List(ctx, iciList, client.MatchingFields{
+		BareMetalHostRefNameIndex:      ref.Name,
+		BareMetalHostRefNamespaceIndex: ref.Namespace,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list ImageClusterInstalls referencing BareMetalHost %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+
+	for i := range iciList.Items {
+		other := &iciList.Items[i]
+		if isSameObject(ici, other) || installationCompleted(other) {
+			continue
+		}
+		return other, nil
+	}
+	return nil, nil
+}
+
+// clusterDeploymentUsers returns the names of the other ImageClusterInstalls referencing the ClusterDeployment of ici
+func (v *imageClusterInstallValidator) clusterDeploymentUsers(ctx context.Context, ici *ImageClusterInstall) ([]string, error) {
+	iciList := &ImageClusterInstallList{}
+	if err := v.List(ctx, iciList, client.InNamespace(ici.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list ImageClusterInstalls in namespace %s: %w", ici.Namespace, err)
+	}
+
+	var names []string
+	for i := range iciList.Items {
+		other := &iciList.Items[i]
+		if isSameObject(ici, other) || other.Spec.ClusterDeploymentRef == nil {
+			continue
+		}
+		if other.Spec.ClusterDeploymentRef.Name == ici.Spec.ClusterDeploymentRef.Name {
+			names = append(names, other.Name)
+		}
+	}
+	return names, nil
+}
+
+// clusterIdentityClaim returns the ImageClusterInstall that has not completed and installs a cluster with the same
+// name and base domain as the ClusterDeployment of ici
+func (v *imageClusterInstallValidator) clusterIdentityClaim(ctx context.Context, ici *ImageClusterInstall) (*ImageClusterInstall, error) {
+	cd := &hivev1.ClusterDeployment{}
+	key := types.NamespacedName{Namespace: ici.Namespace, Name: ici.Spec.ClusterDeploymentRef.Name}
+	if err := v.Get(ctx, key, cd); err != nil {
+		// the ClusterDeployment may be created after the ImageClusterInstall
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := v.List(ctx, cdList); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterDeployments: %w", err)
+	}
+
+	for _, other := range cdList.Items {
+		if other.Namespace == cd.Namespace && other.Name == cd.Name {
+			continue
+		}
+		if other.Spec.ClusterName != cd.Spec.ClusterName || other.Spec.BaseDomain != cd.Spec.BaseDomain {
+			continue
+		}
+		installRef := other.Spec.ClusterInstallRef
+		if installRef == nil || installRef.Group != Group || installRef.Kind != "ImageClusterInstall" {
+			continue
+		}
+
+		otherICI := &ImageClusterInstall{}
+		if err := v.Get(ctx, types.NamespacedName{Namespace: other.Namespace, Name: installRef.Name}, otherICI); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get ImageClusterInstall %s/%s: %w", other.Namespace, installRef.Name, err)
+		}
+		if isSameObject(ici, otherICI) || installationCompleted(otherICI) {
+			continue
+		}
+		return otherICI, nil
+	}
+	return nil, nil
+}
+
+// validateReferences checks the objects referenced by ici for problems the reconciler would otherwise only report
+// once it tries to create the image. Problems are returned as warnings, or as an error in strict mode.
+func (v *imageClusterInstallValidator) validateReferences(ctx context.Context, ici *ImageClusterInstall) (admission.Warnings, error) {
+	problems := []error{}
+	for _, check := range []func(context.Context, *ImageClusterInstall) error{
+		v.validateImageSetRef,
+		v.validateCABundle,
+		v.validateExtraManifestsRefs,
+		v.validatePostInstallManifestsRefs,
+		v.validateSSHKeysSecretRef,
+		v.validateProxySecretRef,
+		v.validateClusterDeploymentRef,
+	} {
+		if err := check(ctx, ici); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	if v.strict {
+		return nil, k8serrors.NewAggregate(problems)
+	}
+	var warnings admission.Warnings
+	for _, problem := range problems {
+		warnings = append(warnings, problem.Error())
+	}
+	return warnings, nil
+}
+
+func (v *imageClusterInstallValidator) validateImageSetRef(ctx context.Context, ici *ImageClusterInstall) error {
+	if ici.Spec.ImageSetRef.Name == "" {
+		return errors.New("imageSetRef is unset")
+	}
+	if err := v.Get(ctx, types.NamespacedName{Name: ici.Spec.ImageSetRef.Name}, &hivev1.ClusterImageSet{}); err != nil {
+		return fmt.Errorf("failed to get ClusterImageSet %s: %w", ici.Spec.ImageSetRef.Name, err)
+	}
+	return nil
+}
+
+func (v *imageClusterInstallValidator) validateCABundle(ctx context.Context, ici *ImageClusterInstall) error {
+	errs := []error{}
+	if ici.Spec.CABundleRef != nil {
+		cm := &corev1.ConfigMap{}
+		if err := v.Get(ctx, types.NamespacedName{Namespace: ici.Namespace, Name: ici.Spec.CABundleRef.Name}, cm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get CA bundle ConfigMap %s: %w", ici.Spec.CABundleRef.Name, err))
+		} else if data, ok := cm.Data[CABundleKey]; !ok {
+			errs = append(errs, fmt.Errorf("CA bundle ConfigMap %s is missing the %s key", cm.Name, CABundleKey))
+		} else if _, err := ParseCABundle([]byte(data)); err != nil {
+			errs = append(errs, fmt.Errorf("CA bundle ConfigMap %s is invalid: %w", cm.Name, err))
+		}
+	}
+
+	for _, source := range ici.Spec.CABundleSources {
+		key := CABundleSourceKey(source)
+		var data []byte
+		var found bool
+		if source.Kind == CABundleSourceKindSecret {
+			secret, err := v.getSecret(ctx, ici.Namespace, source.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get CA bundle Secret %s: %w", source.Name, err))
+				continue
+			}
+			data, found = secret.Data[key]
+		} else {
+			cm := &corev1.ConfigMap{}
+			if err := v.Get(ctx, types.NamespacedName{Namespace: ici.Namespace, Name: source.Name}, cm); err != nil {
+				errs = append(errs, fmt.Errorf("failed to get CA bundle ConfigMap %s: %w", source.Name, err))
+				continue
+			}
+			var content string
+			content, found = cm.Data[key]
+			data = []byte(content)
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("CA bundle %s %s is missing the %s key", CABundleSourceKind(source), source.Name, key))
+		} else if _, err := ParseCABundle(data); err != nil {
+			errs = append(errs, fmt.Errorf("CA bundle %s %s is invalid: %w", CABundleSourceKind(source), source.Name, err))
+		}
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+func (v *imageClusterInstallValidator) validateExtraManifestsRefs(ctx context.Context, ici *ImageClusterInstall) error {
+	errs := []error{}
+	for _, ref := range ici.Spec.ExtraManifestsRefs {
+		cm := &corev1.ConfigMap{}
+		if err := v.Get(ctx, types.NamespacedName{Namespace: ici.Namespace, Name: ref.Name}, cm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get extra manifests ConfigMap %s: %w", ref.Name, err))
+			continue
+		}
+		// templates are only parsed, their content is validated once they are rendered by the controller
+		validate := ValidateExtraManifest
+		if IsExtraManifestsTemplate(cm) {
+			validate = func(content []byte) error {
+				_, err := template.New("").Parse(string(content))
+				return err
+			}
+		}
+		for name, content := range cm.Data {
+			if err := validate([]byte(content)); err != nil {
+				errs = append(errs, fmt.Errorf("extra manifest %s in ConfigMap %s is invalid: %w", name, cm.Name, err))
+			}
+		}
+		for name, content := range cm.BinaryData {
+			if err := validate(content); err != nil {
+				errs = append(errs, fmt.Errorf("extra manifest %s in ConfigMap %s is invalid: %w", name, cm.Name, err))
+			}
+		}
+	}
+	for _, ref := range ici.Spec.ExtraManifestsSecretRefs {
+		secret, err := v.getSecret(ctx, ici.Namespace, ref.Name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get extra manifests Secret %s: %w", ref.Name, err))
+			continue
+		}
+		for name, content := range secret.Data {
+			if err := ValidateExtraManifest(content); err != nil {
+				errs = append(errs, fmt.Errorf("extra manifest %s in Secret %s is invalid: %w", name, secret.Name, err))
+			}
+		}
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+func (v *imageClusterInstallValidator) validatePostInstallManifestsRefs(ctx context.Context, ici *ImageClusterInstall) error {
+	errs := []error{}
+	for _, ref := range ici.Spec.PostInstallManifestsRefs {
+		cm := &corev1.ConfigMap{}
+		if err := v.Get(ctx, types.NamespacedName{Namespace: ici.Namespace, Name: ref.Name}, cm); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get post-install manifests ConfigMap %s: %w", ref.Name, err))
+			continue
+		}
+		for name, content := range cm.Data {
+			if err := ValidateExtraManifest([]byte(content)); err != nil {
+				errs = append(errs, fmt.Errorf("post-install manifest %s in ConfigMap %s is invalid: %w", name, cm.Name, err))
+			}
+		}
+		for name, content := range cm.BinaryData {
+			if err := ValidateExtraManifest(content); err != nil {
+				errs = append(errs, fmt.Errorf("post-install manifest %s in ConfigMap %s is invalid: %w", name, cm.Name, err))
+			}
+		}
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+func (v *imageClusterInstallValidator) validateSSHKeysSecretRef(ctx context.Context, ici *ImageClusterInstall) error {
+	if ici.Spec.SSHKeysSecretRef == nil {
+		return nil
+	}
+
+	secret, err := v.getSecret(ctx, ici.Namespace, ici.Spec.SSHKeysSecretRef.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get SSH keys Secret %s: %w", ici.Spec.SSHKeysSecretRef.Name, err)
+	}
+	keys, ok := secret.Data[SSHKeysSecretKey]
+	if !ok {
+		return fmt.Errorf("SSH keys Secret %s is missing the %s key", secret.Name, SSHKeysSecretKey)
+	}
+	if err := ValidateSSHAuthorizedKeys(string(keys)); err != nil {
+		return fmt.Errorf("invalid ssh key in Secret %s: %w", secret.Name, err)
+	}
+	return nil
+}
+
+func (v *imageClusterInstallValidator) validateProxySecretRef(ctx context.Context, ici *ImageClusterInstall) error {
+	if ici.Spec.Proxy == nil || ici.Spec.Proxy.SecretRef == nil {
+		return nil
+	}
+
+	secret, err := v.getSecret(ctx, ici.Namespace, ici.Spec.Proxy.SecretRef.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get proxy Secret %s: %w", ici.Spec.Proxy.SecretRef.Name, err)
+	}
+	if len(secret.Data[HTTPProxySecretKey]) == 0 && len(secret.Data[HTTPSProxySecretKey]) == 0 {
+		return fmt.Errorf("proxy Secret %s has neither a %s nor a %s key", secret.Name, HTTPProxySecretKey, HTTPSProxySecretKey)
+	}
+	return nil
+}
+
+func (v *imageClusterInstallValidator) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	reader := v.secretReader
+	if reader == nil {
+		reader = v.Reader
+	}
+	secret := &corev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (v *imageClusterInstallValidator) validateClusterDeploymentRef(ctx context.Context, ici *ImageClusterInstall) error {
+	if ici.Spec.ClusterDeploymentRef == nil || ici.Spec.ClusterDeploymentRef.Name == "" {
+		return nil
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := v.Get(ctx, types.NamespacedName{Namespace: ici.Namespace, Name: ici.Spec.ClusterDeploymentRef.Name}, cd); err != nil {
+		// the ClusterDeployment may be created after the ImageClusterInstall
+		return client.IgnoreNotFound(err)
+	}
+
+	ref := cd.Spec.ClusterInstallRef
+	if ref == nil || ref.Group != Group || ref.Kind != "ImageClusterInstall" || ref.Name != ici.Name {
+		return fmt.Errorf("clusterInstallRef of ClusterDeployment %s does not reference ImageClusterInstall %s", cd.Name, ici.Name)
+	}
+	return nil
+}
+
+func isSameObject(a, b *ImageClusterInstall) bool {
+	return a.Namespace == b.Namespace && a.Name == b.Name
+}
+
+func installationCompleted(r *ImageClusterInstall) bool {
+	for _, cond := range r.Status.Conditions {
+		if cond.Type == hivev1.ClusterInstallCompleted {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 var _ webhook.Validator = &ImageClusterInstall{}
 
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *ImageClusterInstall) ValidateCreate() (admission.Warnings, error) {
 	icilog.Info("validate create", "name", r.Name)
+	warnings := deprecationWarnings(r)
 	if err := r.validate(); err != nil {
-		return nil, err
+		return warnings, err
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ImageClusterInstall) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
 	icilog.Info("validate update", "name", r.Name)
+	warnings := deprecationWarnings(r)
+	// objects created before a validation was added must still be deletable
+	if !r.DeletionTimestamp.IsZero() {
+		return warnings, nil
+	}
 
 	if err := r.validate(); err != nil {
-		return nil, err
+		return warnings, err
 	}
 
 	oldClusterInstall, ok := old.(*ImageClusterInstall)
 	if !ok {
-		return nil, fmt.Errorf("old object is not an ImageClusterInstall")
+		return warnings, fmt.Errorf("old object is not an ImageClusterInstall")
 	}
 
 	// Allow update if it's not the spec
 	if !isSpecUpdate(oldClusterInstall, r) {
-		return nil, nil
+		return warnings, nil
 	}
 	// block update if the installation started
 	if installationStarted(oldClusterInstall) {
-		return nil, fmt.Errorf("cannot update ImageClusterInstall when the configImage is ready")
+		return warnings, fmt.Errorf("cannot update ImageClusterInstall when the configImage is ready")
 	}
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -83,6 +606,18 @@ func (r *ImageClusterInstall) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
 
+// deprecationWarnings returns a warning for each deprecated field set in the spec
+func deprecationWarnings(r *ImageClusterInstall) admission.Warnings {
+	var warnings admission.Warnings
+	if r.Spec.NodeIP != "" {
+		warnings = append(warnings, "spec.nodeIP is deprecated and ignored, it will be removed in a future release")
+	}
+	if r.Spec.MachineNetwork != "" {
+		warnings = append(warnings, "spec.machineNetwork is deprecated, use spec.machineNetworks instead")
+	}
+	return warnings
+}
+
 func (r *ImageClusterInstall) validate() error {
 	if err := isValidSSHPublicKey(r.Spec.SSHKey); err != nil {
 		return fmt.Errorf("invalid ssh key: %v", err)
@@ -90,6 +625,9 @@ func (r *ImageClusterInstall) validate() error {
 	if err := isValidHostname(r.Spec.Hostname); err != nil {
 		return fmt.Errorf("invalid hostname: %w", err)
 	}
+	if err := isValidAPIEndpoint(r.Spec.APIEndpointOverride); err != nil {
+		return fmt.Errorf("invalid apiEndpointOverride: %w", err)
+	}
 
 	if err := isValidMachineNetworks(r.Spec.MachineNetwork, r.Spec.MachineNetworks); err != nil {
 		return fmt.Errorf("invalid machine network: %w", err)
@@ -99,6 +637,12 @@ func (r *ImageClusterInstall) validate() error {
 	if err := isValidProxy(r.Spec.Proxy, effectiveMachineNetworks); err != nil {
 		return fmt.Errorf("invalid proxy: %w", err)
 	}
+	if err := ValidatePhaseTimeouts(r.Spec.Timeouts); err != nil {
+		return fmt.Errorf("invalid timeouts: %w", err)
+	}
+	if err := isValidReadinessGates(r.Spec.ReadinessGates); err != nil {
+		return fmt.Errorf("invalid readinessGates: %w", err)
+	}
 	return nil
 }
 
@@ -112,6 +656,12 @@ func isSpecUpdate(oldClusterInstall *ImageClusterInstall, newClusterInstall *Ima
 	newSpec := newClusterInstall.Spec.DeepCopy()
 	oldSpec.ClusterMetadata = nil
 	newSpec.ClusterMetadata = nil
+	// the API endpoint is only used by the operator to reach the installed cluster
+	oldSpec.APIEndpointOverride = ""
+	newSpec.APIEndpointOverride = ""
+	// the defaulter migrating machineNetwork of an existing object is not a change
+	_ = migrateMachineNetwork(oldSpec)
+	_ = migrateMachineNetwork(newSpec)
 
 	return !reflect.DeepEqual(oldSpec, newSpec)
 }
@@ -131,6 +681,184 @@ func isValidSSHPublicKey(pubKeyString string) error {
 	return nil
 }
 
+// ValidateSSHAuthorizedKeys validates each line of an authorized_keys file, empty lines and comments are skipped
+func ValidateSSHAuthorizedKeys(keys string) error {
+	for _, line := range strings.Split(keys, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := isValidSSHPublicKey(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatePhaseTimeouts checks that the set phase timeouts increase in phase order. They are all measured from the
+// time the host was requested to boot, so a phase timeout that isn't longer than the one of an earlier phase can't
+// expire after it.
+func ValidatePhaseTimeouts(timeouts *PhaseTimeouts) error {
+	if timeouts == nil {
+		return nil
+	}
+	phases := []struct {
+		name    string
+		timeout *metav1.Duration
+	}{
+		{"powerOn", timeouts.PowerOn},
+		{"configImageAttached", timeouts.ConfigImageAttached},
+		{"spokeAPIReachable", timeouts.SpokeAPIReachable},
+		{"clusterConverged", timeouts.ClusterConverged},
+	}
+	previous := -1
+	for i, phase := range phases {
+		if phase.timeout == nil {
+			continue
+		}
+		if previous >= 0 && phase.timeout.Duration <= phases[previous].timeout.Duration {
+			return fmt.Errorf("%s %s must be longer than %s %s, phase timeouts are measured from the time the host was requested to boot",
+				phase.name, phase.timeout.Duration, phases[previous].name, phases[previous].timeout.Duration)
+		}
+		previous = i
+	}
+	return nil
+}
+
+// isValidReadinessGates checks that the gates can be evaluated, so a typo doesn't hold the installation in progress
+// until it times out
+func isValidReadinessGates(gates []ReadinessGate) error {
+	for i, gate := range gates {
+		if _, err := schema.ParseGroupVersion(gate.APIVersion); err != nil {
+			return fmt.Errorf("gate %d: invalid apiVersion %s: %w", i, gate.APIVersion, err)
+		}
+		if gate.JSONPath == "" {
+			continue
+		}
+		if gate.Value == "" {
+			return fmt.Errorf("gate %d: value must be set with jsonPath", i)
+		}
+		expression := gate.JSONPath
+		if !strings.HasPrefix(expression, "{") {
+			expression = "{" + expression + "}"
+		}
+		if err := jsonpath.New("readinessGate").Parse(expression); err != nil {
+			return fmt.Errorf("gate %d: invalid jsonPath %s: %w", i, gate.JSONPath, err)
+		}
+	}
+	return nil
+}
+
+// CABundleSourceKind returns the kind of the object referenced by a CABundleSource
+func CABundleSourceKind(source CABundleSource) string {
+	if source.Kind == "" {
+		return CABundleSourceKindConfigMap
+	}
+	return source.Kind
+}
+
+// CABundleSourceKey returns the key of the certificates in the object referenced by a CABundleSource
+func CABundleSourceKey(source CABundleSource) string {
+	if source.Key == "" {
+		return CABundleKey
+	}
+	return source.Key
+}
+
+// ParseCABundle parses the PEM encoded certificates of a CA bundle, every PEM block must be a certificate
+func ParseCABundle(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("PEM block %d is a %s, not a CERTIFICATE", len(certs)+1, block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("PEM block %d is not a valid certificate: %w", len(certs)+1, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, fmt.Errorf("data after PEM block %d is not PEM encoded", len(certs))
+	}
+	return certs, nil
+}
+
+// IsExtraManifestsTemplate returns true when the manifests of an extra manifests ConfigMap are Go templates
+func IsExtraManifestsTemplate(cm *corev1.ConfigMap) bool {
+	return cm.GetAnnotations()[ExtraManifestsTemplateAnnotation] == "true"
+}
+
+// ValidateExtraManifest checks that every document of an extra manifest is a Kubernetes object with an apiVersion,
+// a kind and a metadata.name. The items of a List, or of a kind ending in List such as ConfigMapList, are checked the
+// same way in place of the List. Empty documents are skipped.
+func ValidateExtraManifest(content []byte) error {
+	errs := []error{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for i := 1; ; i++ {
+		var doc map[string]interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// the decoder can't continue after a syntax error
+			errs = append(errs, fmt.Errorf("document %d is not valid YAML: %w", i, err))
+			break
+		}
+		if doc == nil {
+			continue
+		}
+		errs = append(errs, validateManifestObject(doc, fmt.Sprintf("document %d", i))...)
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+// validateManifestObject checks the fields of the object obj, or of its items when it's a List. The List itself only
+// needs an apiVersion and a kind.
+func validateManifestObject(obj map[string]interface{}, path string) []error {
+	fields := [][]string{{"apiVersion"}, {"kind"}, {"metadata", "name"}}
+	kind, _, _ := unstructured.NestedString(obj, "kind")
+	items, isList := obj["items"].([]interface{})
+	isList = isList && strings.HasSuffix(kind, "List")
+	if isList {
+		fields = fields[:2]
+	}
+
+	errs := []error{}
+	missing := []string{}
+	for _, field := range fields {
+		if value, _, _ := unstructured.NestedString(obj, field...); value == "" {
+			missing = append(missing, strings.Join(field, "."))
+		}
+	}
+	if len(missing) > 0 {
+		errs = append(errs, fmt.Errorf("%s is missing %s", path, strings.Join(missing, ", ")))
+	}
+	if !isList {
+		return errs
+	}
+	for i, item := range items {
+		itemPath := fmt.Sprintf("%s item %d", path, i+1)
+		itemObj, ok := item.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s is not an object", itemPath))
+			continue
+		}
+		errs = append(errs, validateManifestObject(itemObj, itemPath)...)
+	}
+	return errs
+}
+
 func isValidHostname(hostname string) error {
 	if hostname == "" {
 		return nil
@@ -142,6 +870,34 @@ func isValidHostname(hostname string) error {
 	return nil
 }
 
+// SplitAPIEndpoint splits an API endpoint into its host and port, the port is empty when the endpoint doesn't set one
+func SplitAPIEndpoint(endpoint string) (string, string) {
+	if host, port, err := net.SplitHostPort(endpoint); err == nil {
+		return host, port
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(endpoint, "["), "]"), ""
+}
+
+// isValidAPIEndpoint checks that endpoint is an IP or a DNS name with an optional port
+func isValidAPIEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	host, port := SplitAPIEndpoint(endpoint)
+	if port != "" {
+		if number, err := strconv.Atoi(port); err != nil || validation.IsValidPortNum(number) != nil {
+			return fmt.Errorf("invalid port %s", port)
+		}
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) != 0 {
+		return errors.New(strings.Join(errs, ";"))
+	}
+	return nil
+}
+
 // isValidMachineNetworks validates both legacy MachineNetwork and new MachineNetworks fields
 func isValidMachineNetworks(legacyMachineNetwork string, machineNetworks []MachineNetworkEntry) error {
 	// If both are specified, MachineNetworks takes precedence but we still validate both
@@ -149,6 +905,9 @@ func isValidMachineNetworks(legacyMachineNetwork string, machineNetworks []Machi
 		if err := isValidNetworkCidr(legacyMachineNetwork); err != nil {
 			return err
 		}
+		if err := isMatchingMachineNetwork(legacyMachineNetwork, machineNetworks); err != nil {
+			return err
+		}
 	}
 
 	for i, network := range machineNetworks {
@@ -160,6 +919,17 @@ func isValidMachineNetworks(legacyMachineNetwork string, machineNetworks []Machi
 	return nil
 }
 
+// isMatchingMachineNetwork checks that the legacy machine network is the first of machineNetworks when both are set
+func isMatchingMachineNetwork(legacyMachineNetwork string, machineNetworks []MachineNetworkEntry) error {
+	if legacyMachineNetwork == "" || len(machineNetworks) == 0 {
+		return nil
+	}
+	if first := machineNetworks[0].CIDR; legacyMachineNetwork != first {
+		return fmt.Errorf("machineNetwork (%s) does not match the first machineNetworks entry (%s)", legacyMachineNetwork, first)
+	}
+	return nil
+}
+
 // getEffectiveMachineNetworks returns the effective machine networks for other validations
 func getEffectiveMachineNetworks(legacyMachineNetwork string, machineNetworks []MachineNetworkEntry, log logr.Logger) []string {
 	if len(machineNetworks) > 0 {
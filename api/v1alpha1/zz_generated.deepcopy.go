@@ -24,6 +24,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -42,6 +43,308 @@ func (in *BareMetalHostReference) DeepCopy() *BareMetalHostReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CABundleSource) DeepCopyInto(out *CABundleSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CABundleSource.
+func (in *CABundleSource) DeepCopy() *CABundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CABundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CACertificate) DeepCopyInto(out *CACertificate) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CACertificate.
+func (in *CACertificate) DeepCopy() *CACertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(CACertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDiagnostics) DeepCopyInto(out *ClusterDiagnostics) {
+	*out = *in
+	out.ConfigMapRef = in.ConfigMapRef
+	in.CollectionTime.DeepCopyInto(&out.CollectionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDiagnostics.
+func (in *ClusterDiagnostics) DeepCopy() *ClusterDiagnostics {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDiagnostics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Concurrency) DeepCopyInto(out *Concurrency) {
+	*out = *in
+	if in.ImageBuilds != nil {
+		in, out := &in.ImageBuilds, &out.ImageBuilds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Concurrency.
+func (in *Concurrency) DeepCopy() *Concurrency {
+	if in == nil {
+		return nil
+	}
+	out := new(Concurrency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultProxy) DeepCopyInto(out *DefaultProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultProxy.
+func (in *DefaultProxy) DeepCopy() *DefaultProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraManifestPolicy) DeepCopyInto(out *ExtraManifestPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraManifestPolicy.
+func (in *ExtraManifestPolicy) DeepCopy() *ExtraManifestPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraManifestPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExtraManifestPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraManifestPolicyList) DeepCopyInto(out *ExtraManifestPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExtraManifestPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraManifestPolicyList.
+func (in *ExtraManifestPolicyList) DeepCopy() *ExtraManifestPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraManifestPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExtraManifestPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraManifestPolicySpec) DeepCopyInto(out *ExtraManifestPolicySpec) {
+	*out = *in
+	if in.AllowedKinds != nil {
+		in, out := &in.AllowedKinds, &out.AllowedKinds
+		*out = make([]ManifestKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedKinds != nil {
+		in, out := &in.DeniedKinds, &out.DeniedKinds
+		*out = make([]ManifestKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxManifestSize != nil {
+		in, out := &in.MaxManifestSize, &out.MaxManifestSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraManifestPolicySpec.
+func (in *ExtraManifestPolicySpec) DeepCopy() *ExtraManifestPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraManifestPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBasedInstallOperatorConfig) DeepCopyInto(out *ImageBasedInstallOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedInstallOperatorConfig.
+func (in *ImageBasedInstallOperatorConfig) DeepCopy() *ImageBasedInstallOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBasedInstallOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageBasedInstallOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBasedInstallOperatorConfigList) DeepCopyInto(out *ImageBasedInstallOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageBasedInstallOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedInstallOperatorConfigList.
+func (in *ImageBasedInstallOperatorConfigList) DeepCopy() *ImageBasedInstallOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBasedInstallOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageBasedInstallOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBasedInstallOperatorConfigSpec) DeepCopyInto(out *ImageBasedInstallOperatorConfigSpec) {
+	*out = *in
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(OperatorTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequeueIntervals != nil {
+		in, out := &in.RequeueIntervals, &out.RequeueIntervals
+		*out = new(RequeueIntervals)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(Concurrency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(InstallDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ISORetention != nil {
+		in, out := &in.ISORetention, &out.ISORetention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedInstallOperatorConfigSpec.
+func (in *ImageBasedInstallOperatorConfigSpec) DeepCopy() *ImageBasedInstallOperatorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBasedInstallOperatorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageBasedInstallOperatorConfigStatus) DeepCopyInto(out *ImageBasedInstallOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageBasedInstallOperatorConfigStatus.
+func (in *ImageBasedInstallOperatorConfigStatus) DeepCopy() *ImageBasedInstallOperatorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageBasedInstallOperatorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageClusterInstall) DeepCopyInto(out *ImageClusterInstall) {
 	*out = *in
@@ -115,6 +418,11 @@ func (in *ImageClusterInstallSpec) DeepCopyInto(out *ImageClusterInstallSpec) {
 		*out = new(hivev1.ClusterMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SSHKeysSecretRef != nil {
+		in, out := &in.SSHKeysSecretRef, &out.SSHKeysSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 	if in.ImageDigestSources != nil {
 		in, out := &in.ImageDigestSources, &out.ImageDigestSources
 		*out = make([]configv1.ImageDigestMirrors, len(*in))
@@ -127,16 +435,38 @@ func (in *ImageClusterInstallSpec) DeepCopyInto(out *ImageClusterInstallSpec) {
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.CABundleSources != nil {
+		in, out := &in.CABundleSources, &out.CABundleSources
+		*out = make([]CABundleSource, len(*in))
+		copy(*out, *in)
+	}
 	if in.ExtraManifestsRefs != nil {
 		in, out := &in.ExtraManifestsRefs, &out.ExtraManifestsRefs
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtraManifestsSecretRefs != nil {
+		in, out := &in.ExtraManifestsSecretRefs, &out.ExtraManifestsSecretRefs
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraManifestsValues != nil {
+		in, out := &in.ExtraManifestsValues, &out.ExtraManifestsValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.BareMetalHostRef != nil {
 		in, out := &in.BareMetalHostRef, &out.BareMetalHostRef
 		*out = new(BareMetalHostReference)
 		**out = **in
 	}
+	if in.NetworkConfigRef != nil {
+		in, out := &in.NetworkConfigRef, &out.NetworkConfigRef
+		*out = new(NetworkConfigReference)
+		**out = **in
+	}
 	if in.MachineNetworks != nil {
 		in, out := &in.MachineNetworks, &out.MachineNetworks
 		*out = make([]MachineNetworkEntry, len(*in))
@@ -145,13 +475,33 @@ func (in *ImageClusterInstallSpec) DeepCopyInto(out *ImageClusterInstallSpec) {
 	if in.Proxy != nil {
 		in, out := &in.Proxy, &out.Proxy
 		*out = new(Proxy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.AdditionalNTPSources != nil {
 		in, out := &in.AdditionalNTPSources, &out.AdditionalNTPSources
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostInstallHealthWindow != nil {
+		in, out := &in.PostInstallHealthWindow, &out.PostInstallHealthWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PostInstallManifestsRefs != nil {
+		in, out := &in.PostInstallManifestsRefs, &out.PostInstallManifestsRefs
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReadinessGates != nil {
+		in, out := &in.ReadinessGates, &out.ReadinessGates
+		*out = make([]ReadinessGate, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(PhaseTimeouts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageClusterInstallSpec.
@@ -180,6 +530,39 @@ func (in *ImageClusterInstallStatus) DeepCopyInto(out *ImageClusterInstallStatus
 		**out = **in
 	}
 	in.BootTime.DeepCopyInto(&out.BootTime)
+	if in.ExtraManifestPolicyViolations != nil {
+		in, out := &in.ExtraManifestPolicyViolations, &out.ExtraManifestPolicyViolations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CACertificates != nil {
+		in, out := &in.CACertificates, &out.CACertificates
+		*out = make([]CACertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastHealthyTime != nil {
+		in, out := &in.LastHealthyTime, &out.LastHealthyTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingReadinessGates != nil {
+		in, out := &in.PendingReadinessGates, &out.PendingReadinessGates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostInstallManifests != nil {
+		in, out := &in.PostInstallManifests, &out.PostInstallManifests
+		*out = make([]PostInstallManifestStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = new(ClusterDiagnostics)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageClusterInstallStatus.
@@ -192,6 +575,58 @@ func (in *ImageClusterInstallStatus) DeepCopy() *ImageClusterInstallStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDefaults) DeepCopyInto(out *InstallDefaults) {
+	*out = *in
+	if in.AdditionalNTPSources != nil {
+		in, out := &in.AdditionalNTPSources, &out.AdditionalNTPSources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(DefaultProxy)
+		**out = **in
+	}
+	if in.ImageDigestSources != nil {
+		in, out := &in.ImageDigestSources, &out.ImageDigestSources
+		*out = make([]configv1.ImageDigestMirrors, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UseHubImageDigestMirrorSets != nil {
+		in, out := &in.UseHubImageDigestMirrorSets, &out.UseHubImageDigestMirrorSets
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MergeGlobalPullSecret != nil {
+		in, out := &in.MergeGlobalPullSecret, &out.MergeGlobalPullSecret
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IPv4MachineNetworkPrefixLength != nil {
+		in, out := &in.IPv4MachineNetworkPrefixLength, &out.IPv4MachineNetworkPrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IPv6MachineNetworkPrefixLength != nil {
+		in, out := &in.IPv6MachineNetworkPrefixLength, &out.IPv6MachineNetworkPrefixLength
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDefaults.
+func (in *InstallDefaults) DeepCopy() *InstallDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineNetworkEntry) DeepCopyInto(out *MachineNetworkEntry) {
 	*out = *in
@@ -207,9 +642,119 @@ func (in *MachineNetworkEntry) DeepCopy() *MachineNetworkEntry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestKind) DeepCopyInto(out *ManifestKind) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestKind.
+func (in *ManifestKind) DeepCopy() *ManifestKind {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestKind)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigReference) DeepCopyInto(out *NetworkConfigReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigReference.
+func (in *NetworkConfigReference) DeepCopy() *NetworkConfigReference {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorTimeouts) DeepCopyInto(out *OperatorTimeouts) {
+	*out = *in
+	if in.Install != nil {
+		in, out := &in.Install, &out.Install
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.PhaseTimeouts.DeepCopyInto(&out.PhaseTimeouts)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorTimeouts.
+func (in *OperatorTimeouts) DeepCopy() *OperatorTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTimeouts) DeepCopyInto(out *PhaseTimeouts) {
+	*out = *in
+	if in.PowerOn != nil {
+		in, out := &in.PowerOn, &out.PowerOn
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ConfigImageAttached != nil {
+		in, out := &in.ConfigImageAttached, &out.ConfigImageAttached
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SpokeAPIReachable != nil {
+		in, out := &in.SpokeAPIReachable, &out.SpokeAPIReachable
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ClusterConverged != nil {
+		in, out := &in.ClusterConverged, &out.ClusterConverged
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTimeouts.
+func (in *PhaseTimeouts) DeepCopy() *PhaseTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostInstallManifestStatus) DeepCopyInto(out *PostInstallManifestStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInstallManifestStatus.
+func (in *PostInstallManifestStatus) DeepCopy() *PostInstallManifestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostInstallManifestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Proxy) DeepCopyInto(out *Proxy) {
 	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
@@ -221,3 +766,48 @@ func (in *Proxy) DeepCopy() *Proxy {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessGate) DeepCopyInto(out *ReadinessGate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessGate.
+func (in *ReadinessGate) DeepCopy() *ReadinessGate {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeueIntervals) DeepCopyInto(out *RequeueIntervals) {
+	*out = *in
+	if in.HostValidation != nil {
+		in, out := &in.HostValidation, &out.HostValidation
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ImageCreation != nil {
+		in, out := &in.ImageCreation, &out.ImageCreation
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.InstallProgress != nil {
+		in, out := &in.InstallProgress, &out.InstallProgress
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeueIntervals.
+func (in *RequeueIntervals) DeepCopy() *RequeueIntervals {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeueIntervals)
+	in.DeepCopyInto(out)
+	return out
+}
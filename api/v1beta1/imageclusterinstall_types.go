@@ -0,0 +1,406 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apicfgv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// ImageClusterInstallSpec defines the desired state of ImageClusterInstall
+type ImageClusterInstallSpec struct {
+	// ClusterDeploymentRef is a reference to the ClusterDeployment.
+	// +optional
+	ClusterDeploymentRef *corev1.LocalObjectReference `json:"clusterDeploymentRef"`
+
+	// ImageSetRef is a reference to a ClusterImageSet.
+	ImageSetRef hivev1.ClusterImageSetReference `json:"imageSetRef"`
+
+	// ClusterMetadata contains metadata information about the installed cluster.
+	// This must be set as soon as all the information is available.
+	// +optional
+	ClusterMetadata *hivev1.ClusterMetadata `json:"clusterMetadata"`
+
+	// Hostname is the desired hostname for the host
+	Hostname string `json:"hostname,omitempty"`
+
+	// SSHKeys are the public Secure Shell (SSH) keys to provide access to
+	// instances. Equivalent to install-config.yaml's sshKey.
+	// These keys will be added to the host to allow ssh access
+	// +optional
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:Pattern=`^[^\n]+$`
+	SSHKeys []string `json:"sshKeys,omitempty"`
+
+	// SSHKeysSecretRef references a Secret in the ImageClusterInstall namespace with one or more SSH authorized
+	// keys, one per line, under the authorized_keys key. They are added to SSHKeys.
+	// +optional
+	SSHKeysSecretRef *corev1.LocalObjectReference `json:"sshKeysSecretRef,omitempty"`
+
+	// ImageDigestSources lists sources/repositories for the release-image content.
+	// +optional
+	ImageDigestSources []apicfgv1.ImageDigestMirrors `json:"imageDigestSources,omitempty"`
+
+	// InheritImageDigestSources adds the hub-wide image digest sources to ImageDigestSources. Without it the hub-wide
+	// sources are only used when ImageDigestSources is empty.
+	// +optional
+	InheritImageDigestSources bool `json:"inheritImageDigestSources,omitempty"`
+
+	// Disconnected marks a host that can only pull images from mirrors. The repository of the release image must be
+	// a source with mirrors in ImageDigestSources.
+	// +optional
+	Disconnected bool `json:"disconnected,omitempty"`
+
+	// CABundle is a reference to a config map containing the new bundle of trusted certificates for the host.
+	// The tls-ca-bundle.pem entry in the config map will be written to /etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem
+	CABundleRef *corev1.LocalObjectReference `json:"caBundleRef,omitempty"`
+
+	// CABundleSources are more ConfigMaps and Secrets containing trusted certificates for the host. Their
+	// certificates are merged with the ones of CABundleRef, duplicates are removed.
+	// +optional
+	CABundleSources []CABundleSource `json:"caBundleSources,omitempty"`
+
+	// ExtraManifestsRefs is list of config map references containing additional manifests to be applied to the relocated cluster.
+	// +optional
+	ExtraManifestsRefs []corev1.LocalObjectReference `json:"extraManifestsRefs,omitempty"`
+
+	// ExtraManifestsSecretRefs is list of secret references containing additional manifests to be applied to the
+	// relocated cluster. Use it for manifests with sensitive data.
+	// +optional
+	ExtraManifestsSecretRefs []corev1.LocalObjectReference `json:"extraManifestsSecretRefs,omitempty"`
+
+	// ExtraManifestsValues are the values available to the templated extra manifests as .Values
+	// +optional
+	ExtraManifestsValues map[string]string `json:"extraManifestsValues,omitempty"`
+
+	// BareMetalHostRef identifies a BareMetalHost object to be used to attach the configuration to the host.
+	// +optional
+	BareMetalHostRef *BareMetalHostReference `json:"bareMetalHostRef,omitempty"`
+
+	// NetworkConfigRef references a Secret or ConfigMap in the ImageClusterInstall namespace with the nmstate
+	// network configuration of the host under the nmstate key.
+	// It takes precedence over the preprovisioning network data of the BareMetalHost.
+	// +optional
+	NetworkConfigRef *NetworkConfigReference `json:"networkConfigRef,omitempty"`
+
+	// MachineNetworks is the list of IP address pools for machines.
+	// This enables dual-stack support by allowing multiple networks.
+	// Equivalent to install-config.yaml's machineNetwork.
+	// +optional
+	MachineNetworks []MachineNetworkEntry `json:"machineNetworks,omitempty"`
+
+	// Proxy defines the proxy settings to be applied in relocated cluster
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// AdditionalNTPSources is a list of NTP sources (hostname or IP) to be added to all cluster
+	// hosts. They are added to any NTP sources that were configured through other means.
+	// +optional
+	AdditionalNTPSources []string `json:"additionalNTPSources,omitempty"`
+
+	// APIEndpointOverride is the address the operator uses to reach the API of the installed cluster instead of the
+	// server of its admin kubeconfig, as an IP or host with an optional port, e.g. 192.0.2.10:6443. Use it when the hub
+	// can't resolve the DNS name of the cluster. The certificate of the API server is still validated against the host
+	// of the admin kubeconfig server. It can be changed after the installation started.
+	// +optional
+	APIEndpointOverride string `json:"apiEndpointOverride,omitempty"`
+
+	// PostInstallHealthWindow is how long the health of the cluster is watched after it was installed. The health is
+	// reported by the ClusterHealthy condition and doesn't change the install conditions.
+	// +optional
+	PostInstallHealthWindow *metav1.Duration `json:"postInstallHealthWindow,omitempty"`
+
+	// PostInstallManifestsRefs are ConfigMaps with manifests applied in order to the installed cluster with
+	// server-side apply once it is installed. Use them for objects that can't be applied during the reconfiguration,
+	// such as OLM Subscriptions and the custom resources of the operators they install. They aren't templated.
+	// +optional
+	PostInstallManifestsRefs []corev1.LocalObjectReference `json:"postInstallManifestsRefs,omitempty"`
+
+	// ReadinessGates must all pass, in addition to the cluster version and the nodes being ready, before the
+	// installation is reported as completed. They are evaluated on the installed cluster.
+	// +optional
+	ReadinessGates []ReadinessGate `json:"readinessGates,omitempty"`
+
+	// WaitForPostInstallManifests keeps the installation in progress until all the post-install manifests are applied
+	// +optional
+	WaitForPostInstallManifests bool `json:"waitForPostInstallManifests,omitempty"`
+
+	// Timeouts overrides the operator timeouts for this installation
+	// +optional
+	Timeouts *Timeouts `json:"timeouts,omitempty"`
+
+	// RetryPolicy controls how failed steps of this installation are retried
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// Timeouts defines the timeouts of an installation
+type Timeouts struct {
+	// Install is the time the cluster has to finish installing after the host was requested to boot.
+	// Defaults to the operator install timeout.
+	// +optional
+	Install *metav1.Duration `json:"install,omitempty"`
+
+	// PowerOn is the time the host has to power on after it was requested to boot
+	// +optional
+	PowerOn *metav1.Duration `json:"powerOn,omitempty"`
+
+	// ConfigImageAttached is the time the configuration image has to be attached to the host after it was requested
+	// to boot
+	// +optional
+	ConfigImageAttached *metav1.Duration `json:"configImageAttached,omitempty"`
+
+	// SpokeAPIReachable is the time the API of the installed cluster has to answer after the host was requested to
+	// boot
+	// +optional
+	SpokeAPIReachable *metav1.Duration `json:"spokeAPIReachable,omitempty"`
+
+	// ClusterConverged is the time the cluster version and the nodes of the installed cluster have to be ready after
+	// the host was requested to boot
+	// +optional
+	ClusterConverged *metav1.Duration `json:"clusterConverged,omitempty"`
+}
+
+// RetryPolicy defines how failed steps of an installation are retried
+type RetryPolicy struct {
+	// ImageCreationInterval is the time to wait before retrying a failed image creation.
+	// Failed image creations are retried with an exponential backoff when unset.
+	// +optional
+	ImageCreationInterval *metav1.Duration `json:"imageCreationInterval,omitempty"`
+}
+
+// ReadinessGate is a condition on an object of the installed cluster that must pass before the installation is
+// reported as completed. Exactly one of ConditionType and JSONPath must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.conditionType) != has(self.jsonPath)",message="exactly one of conditionType and jsonPath must be set"
+type ReadinessGate struct {
+	// APIVersion is the group and version of the object, e.g. apps/v1
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the object
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, unset for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object
+	Name string `json:"name"`
+
+	// ConditionType is the type of the condition in the status of the object that must have ConditionStatus
+	// +optional
+	ConditionType string `json:"conditionType,omitempty"`
+
+	// ConditionStatus is the expected status of the condition, defaults to True
+	// +optional
+	ConditionStatus string `json:"conditionStatus,omitempty"`
+
+	// JSONPath is a JSONPath expression evaluated on the object, e.g. {.status.syncStatus}
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Value is the expected result of JSONPath
+	// +optional
+	Value string `json:"value,omitempty"`
+}
+
+// ImageClusterInstallStatus defines the observed state of ImageClusterInstall
+type ImageClusterInstallStatus struct {
+	// Conditions is a list of conditions associated with syncing to the cluster.
+	// +optional
+	Conditions []hivev1.ClusterInstallCondition `json:"conditions,omitempty"`
+
+	// InstallRestarts is the total count of container restarts on the clusters install job.
+	InstallRestarts int `json:"installRestarts,omitempty"`
+
+	BareMetalHostRef *BareMetalHostReference `json:"bareMetalHostRef,omitempty"`
+
+	// BootTime indicates the time at which the host was requested to boot. Used to determine install timeouts.
+	BootTime metav1.Time `json:"bootTime,omitempty"`
+
+	// ExtraManifestPolicyViolations are the violations of the ExtraManifestPolicy by the extra manifests
+	// +optional
+	ExtraManifestPolicyViolations []string `json:"extraManifestPolicyViolations,omitempty"`
+
+	// CACertificates are the trusted certificates included in the configuration image
+	// +optional
+	CACertificates []CACertificate `json:"caCertificates,omitempty"`
+
+	// LastHealthyTime is the last time the installed cluster was seen healthy during the post-install health window
+	// +optional
+	LastHealthyTime *metav1.Time `json:"lastHealthyTime,omitempty"`
+
+	// PendingReadinessGates are the readiness gates that don't pass yet and why
+	// +optional
+	PendingReadinessGates []string `json:"pendingReadinessGates,omitempty"`
+
+	// PostInstallManifests are the results of applying the post-install manifests to the installed cluster
+	// +optional
+	PostInstallManifests []PostInstallManifestStatus `json:"postInstallManifests,omitempty"`
+
+	// Diagnostics references the diagnostics collected from the cluster when the installation failed
+	// +optional
+	Diagnostics *ClusterDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// ClusterDiagnostics references the diagnostics collected from the cluster when the installation failed
+type ClusterDiagnostics struct {
+	// ConfigMapRef references the ConfigMap in the ImageClusterInstall namespace that holds the diagnostics
+	ConfigMapRef corev1.LocalObjectReference `json:"configMapRef"`
+	// Reason is the reason of the failure the diagnostics were collected for
+	Reason string `json:"reason"`
+	// CollectionTime is the time the diagnostics were collected
+	CollectionTime metav1.Time `json:"collectionTime"`
+	// Truncated is true when some of the diagnostics were truncated to fit in the ConfigMap
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+const (
+	PostInstallManifestPending = "Pending"
+	PostInstallManifestApplied = "Applied"
+	PostInstallManifestFailed  = "Failed"
+)
+
+// PostInstallManifestStatus is the result of applying a post-install manifest to the installed cluster
+type PostInstallManifestStatus struct {
+	// APIVersion is the group and version of the object
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the object
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the object, unset for cluster-scoped objects
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object
+	Name string `json:"name"`
+	// State is Pending, Applied or Failed
+	// +kubebuilder:validation:Enum=Pending;Applied;Failed
+	State string `json:"state"`
+	// Message is the error of the last failed attempt
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Attempts is the number of times the object was applied
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// LastAttemptTime is the last time the object was applied
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+type BareMetalHostReference struct {
+	// Name identifies the BareMetalHost within a namespace
+	Name string `json:"name"`
+	// Namespace identifies the namespace containing the referenced BareMetalHost
+	Namespace string `json:"namespace"`
+}
+
+// CABundleSource references a ConfigMap or Secret key containing PEM encoded certificates
+type CABundleSource struct {
+	// Kind is the kind of the referenced object, ConfigMap or Secret
+	// +kubebuilder:validation:Enum=ConfigMap;Secret
+	// +kubebuilder:default:=ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name identifies the referenced object within the ImageClusterInstall namespace
+	Name string `json:"name"`
+	// Key is the key of the certificates in the referenced object, tls-ca-bundle.pem when empty
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// CACertificate identifies a trusted certificate
+type CACertificate struct {
+	// Fingerprint is the SHA-256 fingerprint of the certificate
+	Fingerprint string `json:"fingerprint"`
+	// Subject is the subject of the certificate
+	Subject string `json:"subject"`
+	// NotAfter is the time the certificate expires
+	NotAfter metav1.Time `json:"notAfter"`
+}
+
+// NetworkConfigReference references an object containing an nmstate network configuration
+type NetworkConfigReference struct {
+	// Kind is the kind of the referenced object, Secret or ConfigMap
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default:=Secret
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name identifies the referenced object within the ImageClusterInstall namespace
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:storageversion
+// +kubebuilder:resource:path=imageclusterinstalls,shortName=ici
+// +kubebuilder:printcolumn:name="RequirementsMet",type="string",JSONPath=".status.conditions[?(@.type=='RequirementsMet')].reason"
+// +kubebuilder:printcolumn:name="Completed",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].reason"
+// +kubebuilder:printcolumn:name="BareMetalHostRef",type="string",JSONPath=".spec.bareMetalHostRef.name"
+
+// ImageClusterInstall is the Schema for the imageclusterinstall API
+type ImageClusterInstall struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImageClusterInstallSpec   `json:"spec,omitempty"`
+	Status ImageClusterInstallStatus `json:"status,omitempty"`
+}
+
+// Proxy defines the proxy settings for the cluster.
+// At least one of HTTPProxy, HTTPSProxy or SecretRef is required.
+type Proxy struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a list of domains and CIDRs for which the proxy should not be used.
+	// +optional
+	// +kubebuilder:validation:items:MinLength=1
+	// +kubebuilder:validation:items:Pattern=`^[^,]+$`
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// SecretRef references a Secret in the ImageClusterInstall namespace with the proxy URLs under the httpProxy and
+	// httpsProxy keys. Use it for URLs that contain credentials, its values take precedence over HTTPProxy and
+	// HTTPSProxy.
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+// MachineNetworkEntry is a single IP address block for node IP blocks.
+type MachineNetworkEntry struct {
+	// CIDR is the IP block address pool for machines within the cluster.
+	CIDR string `json:"cidr"`
+}
+
+//+kubebuilder:object:root=true
+
+// ImageClusterInstallList contains a list of ImageClusterInstall
+type ImageClusterInstallList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageClusterInstall `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageClusterInstall{}, &ImageClusterInstallList{})
+}
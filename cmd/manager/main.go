@@ -53,9 +53,11 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/api/v1beta1"
 	"github.com/openshift/image-based-install-operator/controllers"
 	"github.com/openshift/image-based-install-operator/internal/credentials"
 	"github.com/openshift/image-based-install-operator/internal/installer"
+	"github.com/openshift/image-based-install-operator/internal/isobuilder"
 	"github.com/openshift/image-based-install-operator/internal/monitor"
 	"github.com/openshift/image-based-install-operator/internal/tlsconfig"
 	//+kubebuilder:scaffold:imports
@@ -69,6 +71,7 @@ var (
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
 	utilruntime.Must(bmh_v1alpha1.AddToScheme(scheme))
 	utilruntime.Must(hivev1.AddToScheme(scheme))
 	utilruntime.Must(configv1.AddToScheme(scheme))
@@ -79,7 +82,10 @@ func main() {
 	var enableLeaderElection bool
 	var runWithPPROF bool
 	var probeAddr string
+	var metricsAddr string
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&metricsAddr, "metrics-bind-address", "0",
+		"The address the metrics endpoint binds to. Use \"0\" to disable serving metrics.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
@@ -112,7 +118,7 @@ func main() {
 	mgr, err := ctrl.NewManager(restCfg, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress: "0",
+			BindAddress: metricsAddr,
 		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -167,6 +173,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	imageBuilder := isobuilder.NewPool(controllerOptions.ImageBuildConcurrency, controllerOptions.ImageBuildQueueSize, logger)
+	if err = mgr.Add(imageBuilder); err != nil {
+		setupLog.Error(err, "unable to add image build pool to manager")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.ImageClusterInstallReconciler{
 		Client:          mgr.GetClient(),
 		Credentials:     credentialsManager,
@@ -176,6 +188,7 @@ func main() {
 		BaseURL:         baseURL,
 		NoncachedClient: mgr.GetAPIReader(),
 		Installer:       installer.NewInstaller(),
+		ImageBuilder:    imageBuilder,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ImageClusterInstall")
 		os.Exit(1)
@@ -185,14 +198,27 @@ func main() {
 		Client:                       mgr.GetClient(),
 		Log:                          logger,
 		Scheme:                       mgr.GetScheme(),
-		DefaultInstallTimeout:        time.Hour,
+		DefaultInstallTimeout:        controllers.DefaultInstallTimeout,
 		GetSpokeClusterInstallStatus: monitor.GetClusterInstallStatus,
+		GetReadinessGatesStatus:      monitor.GetReadinessGatesStatus,
+		ApplyPostInstallManifest:     monitor.ApplyManifest,
+		CollectSpokeDiagnostics:      monitor.CollectDiagnostics,
 		Options:                      controllerOptions,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create monitor", "controller", "ImageClusterInstallMonitor")
 		os.Exit(1)
 	}
 
+	if err = (&controllers.OperatorConfigReconciler{
+		Client:       mgr.GetClient(),
+		Log:          logger,
+		Options:      controllerOptions,
+		ImageBuilder: imageBuilder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+		os.Exit(1)
+	}
+
 	if err := (&crtls.SecurityProfileWatcher{
 		Client:                    mgr.GetClient(),
 		InitialTLSAdherencePolicy: tlsResult.TLSAdherencePolicy,
@@ -212,7 +238,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&v1alpha1.ImageClusterInstall{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&v1alpha1.ImageClusterInstall{}).SetupWebhookWithManager(mgr, controllerOptions.StrictAdmissionValidation); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "ImageClusterInstall")
 		os.Exit(1)
 	}
@@ -0,0 +1,310 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// render creates the configuration iso of an ImageClusterInstall without a hub.
+// The ImageClusterInstall and the objects it references are read from manifest files.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/api/v1beta1"
+	"github.com/openshift/image-based-install-operator/controllers"
+	"github.com/openshift/image-based-install-operator/internal/credentials"
+	"github.com/openshift/image-based-install-operator/internal/installer"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(v1beta1.AddToScheme(scheme))
+	utilruntime.Must(bmh_v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(hivev1.AddToScheme(scheme))
+}
+
+type fileList []string
+
+func (f *fileList) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	var (
+		files     fileList
+		name      string
+		namespace string
+		outputDir string
+		strict    bool
+	)
+	flag.Var(&files, "f", "Manifest file or directory to read objects from, can be repeated")
+	flag.StringVar(&name, "name", "", "Name of the ImageClusterInstall to render, required when the manifests contain more than one")
+	flag.StringVar(&namespace, "namespace", "default", "Namespace of objects that don't set one")
+	flag.StringVar(&outputDir, "output-dir", "", "Directory to write the configuration iso and the auth files to")
+	flag.BoolVar(&strict, "strict-validation", false, "Fail on problems found in the referenced objects instead of warning, like a hub with STRICT_ADMISSION_VALIDATION")
+	flag.Parse()
+
+	log := logrus.New()
+	if len(files) == 0 || outputDir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := render(context.Background(), log, files, name, namespace, outputDir, strict); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func render(ctx context.Context, log logrus.FieldLogger, files []string, name, namespace, outputDir string, strict bool) error {
+	objs, err := readObjects(files)
+	if err != nil {
+		return err
+	}
+
+	ici, err := findClusterInstall(objs, name)
+	if err != nil {
+		return err
+	}
+	if ici.Namespace == "" {
+		ici.Namespace = namespace
+	}
+	// the reconciler reads cluster scoped ClusterImageSets with the ImageClusterInstall namespace and the validation
+	// without one, the fake client serves them under both
+	clusterScoped := []client.Object{}
+	for _, obj := range objs {
+		if imageSet, ok := obj.(*hivev1.ClusterImageSet); ok {
+			imageSet := imageSet.DeepCopy()
+			imageSet.Namespace = ""
+			clusterScoped = append(clusterScoped, imageSet)
+			obj.SetNamespace(ici.Namespace)
+		} else if obj.GetNamespace() == "" {
+			obj.SetNamespace(ici.Namespace)
+		}
+	}
+	objs = append(objs, clusterScoped...)
+
+	// the fake client serves the objects read from the manifests to the reconciler in place of the hub
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		Build()
+	r := &controllers.ImageClusterInstallReconciler{
+		Client: c,
+		Credentials: credentials.Credentials{
+			Client: c,
+			Log:    log,
+			Scheme: scheme,
+		},
+		Log:             log,
+		Scheme:          scheme,
+		Options:         &controllers.ImageClusterInstallReconcilerOptions{StrictAdmissionValidation: strict},
+		NoncachedClient: c,
+		Installer:       installer.NewInstaller(),
+	}
+
+	workDir, err := os.MkdirTemp("", "render-")
+	if err != nil {
+		return fmt.Errorf("failed to create tempdir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	log.Infof("Rendering configuration iso for ImageClusterInstall %s/%s", ici.Namespace, ici.Name)
+	if err := r.RenderImage(ctx, log, ici, workDir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for _, file := range controllers.RenderedFiles(workDir) {
+		rel, err := filepath.Rel(workDir, file)
+		if err != nil {
+			return err
+		}
+		if err := copyFile(file, filepath.Join(outputDir, rel)); err != nil {
+			return err
+		}
+		log.Infof("Wrote %s", filepath.Join(outputDir, rel))
+	}
+
+	return nil
+}
+
+// readObjects decodes all the documents in files, directories are read non-recursively
+func readObjects(files []string) ([]client.Object, error) {
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+	objs := []client.Object{}
+	for _, path := range files {
+		paths, err := manifestPaths(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			docs, err := readDocuments(p)
+			if err != nil {
+				return nil, err
+			}
+			for _, doc := range docs {
+				runtimeObj, _, err := decoder.Decode(doc, nil, nil)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode object in %s: %w", p, err)
+				}
+				obj, ok := runtimeObj.(client.Object)
+				if !ok {
+					return nil, fmt.Errorf("unsupported object %T in %s", runtimeObj, p)
+				}
+				if secret, ok := obj.(*corev1.Secret); ok {
+					mergeStringData(secret)
+				}
+				// the reconciler works on v1alpha1 like the API server serves it to the operator
+				if hub, ok := obj.(*v1beta1.ImageClusterInstall); ok {
+					ici := &v1alpha1.ImageClusterInstall{}
+					if err := ici.ConvertFrom(hub); err != nil {
+						return nil, fmt.Errorf("failed to convert ImageClusterInstall %s in %s: %w", hub.Name, p, err)
+					}
+					obj = ici
+				}
+				objs = append(objs, obj)
+			}
+		}
+	}
+	return objs, nil
+}
+
+func manifestPaths(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(path, entry.Name()))
+	}
+	return paths, nil
+}
+
+func readDocuments(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	docs := [][]byte{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// mergeStringData moves stringData into data like the API server does when a secret is created
+func mergeStringData(secret *corev1.Secret) {
+	if len(secret.StringData) == 0 {
+		return
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for key, value := range secret.StringData {
+		secret.Data[key] = []byte(value)
+	}
+	secret.StringData = nil
+}
+
+func findClusterInstall(objs []client.Object, name string) (*v1alpha1.ImageClusterInstall, error) {
+	found := []*v1alpha1.ImageClusterInstall{}
+	for _, obj := range objs {
+		ici, ok := obj.(*v1alpha1.ImageClusterInstall)
+		if !ok || (name != "" && ici.Name != name) {
+			continue
+		}
+		found = append(found, ici)
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, errors.New("no ImageClusterInstall found in the manifests")
+	case 1:
+		return found[0], nil
+	default:
+		return nil, errors.New("more than one ImageClusterInstall found in the manifests, select one with --name")
+	}
+}
+
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(dst), err)
+	}
+	if err := os.WriteFile(dst, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+func TestRender(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Render Suite")
+}
+
+const manifests = `
+apiVersion: extensions.hive.openshift.io/v1alpha1
+kind: ImageClusterInstall
+metadata:
+  name: ici
+  namespace: test
+spec:
+  imageSetRef:
+    name: imageset
+---
+apiVersion: hive.openshift.io/v1
+kind: ClusterImageSet
+metadata:
+  name: imageset
+spec:
+  releaseImage: quay.io/openshift-release-dev/ocp-release:4.16.0-x86_64
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: ps
+  namespace: test
+stringData:
+  .dockerconfigjson: '{"auths":{}}'
+`
+
+var _ = Describe("readObjects", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "render-test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("reads all documents from the manifests in a directory", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "objects.yaml"), []byte(manifests), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0600)).To(Succeed())
+
+		objs, err := readObjects([]string{dir})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(3))
+		Expect(objs[0]).To(BeAssignableToTypeOf(&v1alpha1.ImageClusterInstall{}))
+		Expect(objs[1]).To(BeAssignableToTypeOf(&hivev1.ClusterImageSet{}))
+
+		secret, ok := objs[2].(*corev1.Secret)
+		Expect(ok).To(BeTrue())
+		Expect(secret.StringData).To(BeEmpty())
+		Expect(secret.Data[corev1.DockerConfigJsonKey]).To(Equal([]byte(`{"auths":{}}`)))
+	})
+
+	It("converts v1beta1 ImageClusterInstalls to v1alpha1", func() {
+		path := filepath.Join(dir, "ici.yaml")
+		Expect(os.WriteFile(path, []byte(`apiVersion: extensions.hive.openshift.io/v1beta1
+kind: ImageClusterInstall
+metadata:
+  name: ici
+spec:
+  imageSetRef:
+    name: imageset
+  sshKeys:
+  - ssh-rsa AAAA one
+  - ssh-rsa AAAA two
+`), 0600)).To(Succeed())
+
+		objs, err := readObjects([]string{path})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(1))
+		ici, ok := objs[0].(*v1alpha1.ImageClusterInstall)
+		Expect(ok).To(BeTrue())
+		Expect(ici.Spec.SSHKey).To(Equal("ssh-rsa AAAA one\nssh-rsa AAAA two"))
+	})
+
+	It("fails on objects of unknown kinds", func() {
+		path := filepath.Join(dir, "unknown.yaml")
+		Expect(os.WriteFile(path, []byte("apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: thing\n"), 0600)).To(Succeed())
+
+		_, err := readObjects([]string{path})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("findClusterInstall", func() {
+	It("requires a name when there are multiple ImageClusterInstalls", func() {
+		first := &v1alpha1.ImageClusterInstall{}
+		first.Name = "first"
+		second := &v1alpha1.ImageClusterInstall{}
+		second.Name = "second"
+
+		_, err := findClusterInstall([]client.Object{first, second}, "")
+		Expect(err).To(HaveOccurred())
+
+		ici, err := findClusterInstall([]client.Object{first, second}, "second")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ici).To(Equal(second))
+	})
+
+	It("fails when there is no ImageClusterInstall", func() {
+		_, err := findClusterInstall([]client.Object{&corev1.Secret{}}, "")
+		Expect(err).To(HaveOccurred())
+	})
+})
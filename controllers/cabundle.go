@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+// caCertificateExpiryWarningPeriod is how long before they expire the trusted certificates are reported
+const caCertificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// caBundleSources returns the sources of the trusted certificates, caBundleRef first
+func caBundleSources(ici *v1alpha1.ImageClusterInstall) []v1alpha1.CABundleSource {
+	sources := []v1alpha1.CABundleSource{}
+	if ici.Spec.CABundleRef != nil {
+		sources = append(sources, v1alpha1.CABundleSource{Kind: v1alpha1.CABundleSourceKindConfigMap, Name: ici.Spec.CABundleRef.Name})
+	}
+	return append(sources, ici.Spec.CABundleSources...)
+}
+
+// caCertificates reads and parses the trusted certificates of all the CA bundle sources of the ImageClusterInstall,
+// certificates found in several sources are only returned once. Without sources the default CA bundle of the operator
+// config is used.
+func (r *ImageClusterInstallReconciler) caCertificates(ctx context.Context, ici *v1alpha1.ImageClusterInstall) ([]*x509.Certificate, error) {
+	if len(caBundleSources(ici)) == 0 {
+		config, err := getOperatorConfig(ctx, r.Client, r.Log)
+		if err != nil {
+			return nil, err
+		}
+		if caBundle := config.defaults().CABundle; caBundle != "" {
+			return v1alpha1.ParseCABundle([]byte(caBundle))
+		}
+		return nil, nil
+	}
+
+	certs := []*x509.Certificate{}
+	seen := map[[sha256.Size]byte]bool{}
+	errs := []error{}
+	for _, source := range caBundleSources(ici) {
+		data, err := r.caBundleSourceData(ctx, ici.Namespace, source)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sourceCerts, err := v1alpha1.ParseCABundle(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid CA bundle in %s %s: %w", v1alpha1.CABundleSourceKind(source), source.Name, err))
+			continue
+		}
+		for _, cert := range sourceCerts {
+			sum := sha256.Sum256(cert.Raw)
+			if seen[sum] {
+				continue
+			}
+			seen[sum] = true
+			certs = append(certs, cert)
+		}
+	}
+	if err := k8serrors.NewAggregate(errs); err != nil {
+		return nil, err
+	}
+	return certs, nil
+}
+
+func (r *ImageClusterInstallReconciler) caBundleSourceData(ctx context.Context, namespace string, source v1alpha1.CABundleSource) ([]byte, error) {
+	kind := v1alpha1.CABundleSourceKind(source)
+	key := v1alpha1.CABundleSourceKey(source)
+	objKey := types.NamespacedName{Name: source.Name, Namespace: namespace}
+	if kind == v1alpha1.CABundleSourceKindSecret {
+		secret := &corev1.Secret{}
+		if err := r.NoncachedClient.Get(ctx, objKey, secret); err != nil {
+			return nil, fmt.Errorf("failed to get CA bundle Secret %s: %w", source.Name, err)
+		}
+		if data, ok := secret.Data[key]; ok {
+			return data, nil
+		}
+	} else {
+		cm := &corev1.ConfigMap{}
+		if err := r.Get(ctx, objKey, cm); err != nil {
+			return nil, fmt.Errorf("failed to get CA bundle ConfigMap %s: %w", source.Name, err)
+		}
+		if data, ok := cm.Data[key]; ok {
+			return []byte(data), nil
+		}
+	}
+	return nil, fmt.Errorf("%s key missing from CA bundle %s %s", key, kind, source.Name)
+}
+
+// getCABundle returns the PEM encoded trusted certificates of the ImageClusterInstall
+func (r *ImageClusterInstallReconciler) getCABundle(ctx context.Context, ici *v1alpha1.ImageClusterInstall) (string, error) {
+	certs, err := r.caCertificates(ctx, ici)
+	if err != nil {
+		return "", err
+	}
+	return encodeCABundle(certs), nil
+}
+
+func encodeCABundle(certs []*x509.Certificate) string {
+	bundle := strings.Builder{}
+	for _, cert := range certs {
+		bundle.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	}
+	return bundle.String()
+}
+
+// checkCABundle validates the trusted certificates, every CA bundle source must only contain certificates
+func (r *ImageClusterInstallReconciler) checkCABundle(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cond *hivev1.ClusterInstallCondition) ([]*x509.Certificate, error) {
+
+	certs, err := r.caCertificates(ctx, ici)
+	if err != nil {
+		cond.Reason = v1alpha1.CABundleInvalidReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return nil, err
+	}
+	return certs, nil
+}
+
+// caCertificateFingerprint returns the SHA-256 fingerprint of a certificate in the colon separated form openssl uses
+func caCertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+func caCertificatesStatus(certs []*x509.Certificate) []v1alpha1.CACertificate {
+	if len(certs) == 0 {
+		return nil
+	}
+	status := make([]v1alpha1.CACertificate, len(certs))
+	for i, cert := range certs {
+		status[i] = v1alpha1.CACertificate{
+			Fingerprint: caCertificateFingerprint(cert),
+			Subject:     cert.Subject.String(),
+			NotAfter:    metav1.NewTime(cert.NotAfter.UTC()),
+		}
+	}
+	return status
+}
+
+// setCACertificates records the trusted certificates included in the configuration image in the ImageClusterInstall status
+func (r *ImageClusterInstallReconciler) setCACertificates(ctx context.Context, ici *v1alpha1.ImageClusterInstall, certs []*x509.Certificate) error {
+	status := caCertificatesStatus(certs)
+	if slices.EqualFunc(ici.Status.CACertificates, status, func(a, b v1alpha1.CACertificate) bool {
+		return a.Fingerprint == b.Fingerprint
+	}) {
+		return nil
+	}
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Status.CACertificates = status
+	return r.Status().Patch(ctx, ici, patch)
+}
+
+// caCertificateExpiryWarnings returns a warning for each certificate that expired or expires within the warning period
+func caCertificateExpiryWarnings(certs []*x509.Certificate, now time.Time) []string {
+	warnings := []string{}
+	for _, cert := range certs {
+		notAfter := cert.NotAfter.UTC().Format(time.RFC3339)
+		if now.After(cert.NotAfter) {
+			warnings = append(warnings, fmt.Sprintf("CA certificate %s (%s) expired on %s", cert.Subject, caCertificateFingerprint(cert), notAfter))
+		} else if cert.NotAfter.Sub(now) < caCertificateExpiryWarningPeriod {
+			warnings = append(warnings, fmt.Sprintf("CA certificate %s (%s) expires on %s", cert.Subject, caCertificateFingerprint(cert), notAfter))
+		}
+	}
+	return warnings
+}
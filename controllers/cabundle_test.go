@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// testCACertificate returns a PEM encoded self-signed CA certificate
+func testCACertificate(commonName string, notAfter time.Time) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func parseTestCACertificate(commonName string, notAfter time.Time) *x509.Certificate {
+	certs, err := v1alpha1.ParseCABundle([]byte(testCACertificate(commonName, notAfter)))
+	Expect(err).NotTo(HaveOccurred())
+	return certs[0]
+}
+
+var _ = Describe("caCertificateExpiryWarnings", func() {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	It("reports expired and expiring certificates", func() {
+		expired := parseTestCACertificate("expired", now.Add(-time.Hour))
+		expiring := parseTestCACertificate("expiring", now.Add(7*24*time.Hour))
+		valid := parseTestCACertificate("valid", now.Add(365*24*time.Hour))
+
+		warnings := caCertificateExpiryWarnings([]*x509.Certificate{expired, expiring, valid}, now)
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0]).To(Equal("CA certificate CN=expired (" + caCertificateFingerprint(expired) + ") expired on 2024-05-31T23:00:00Z"))
+		Expect(warnings[1]).To(Equal("CA certificate CN=expiring (" + caCertificateFingerprint(expiring) + ") expires on 2024-06-08T00:00:00Z"))
+	})
+})
+
+var _ = Describe("caCertificatesStatus", func() {
+	It("records the fingerprint, subject and expiry of each certificate", func() {
+		notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		cert := parseTestCACertificate("site", notAfter)
+
+		status := caCertificatesStatus([]*x509.Certificate{cert})
+		Expect(status).To(HaveLen(1))
+		Expect(status[0].Subject).To(Equal("CN=site"))
+		Expect(status[0].NotAfter.Time).To(Equal(notAfter))
+		Expect(status[0].Fingerprint).To(MatchRegexp("^([0-9A-F]{2}:){31}[0-9A-F]{2}$"))
+		Expect(encodeCABundle([]*x509.Certificate{cert})).To(Equal(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))))
+	})
+
+	It("is empty without certificates", func() {
+		Expect(caCertificatesStatus(nil)).To(BeNil())
+	})
+})
@@ -2,7 +2,7 @@ package controllers
 
 import (
 	"context"
-	"fmt"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -119,9 +119,18 @@ func (r *ImageClusterInstallReconciler) setRequirementsMetCondition(ctx context.
 	}
 }
 
+// timeoutReasons are the reasons of the failed condition of an installation that timed out
+var timeoutReasons = []string{
+	v1alpha1.InstallTimedoutReason,
+	v1alpha1.HostPowerOnTimedoutReason,
+	v1alpha1.ConfigImageAttachTimedoutReason,
+	v1alpha1.SpokeAPIReachableTimedoutReason,
+	v1alpha1.ClusterConvergenceTimedoutReason,
+}
+
 func installationTimedout(ici *v1alpha1.ImageClusterInstall) bool {
 	cond := findCondition(ici.Status.Conditions, hivev1.ClusterInstallFailed)
-	return cond != nil && cond.Status == corev1.ConditionTrue && cond.Reason == v1alpha1.InstallTimedoutReason
+	return cond != nil && cond.Status == corev1.ConditionTrue && slices.Contains(timeoutReasons, cond.Reason)
 }
 
 func InstallationCompleted(ici *v1alpha1.ImageClusterInstall) bool {
@@ -130,17 +139,27 @@ func InstallationCompleted(ici *v1alpha1.ImageClusterInstall) bool {
 }
 
 func (r *ImageClusterInstallMonitor) setClusterInstallingConditions(ctx context.Context, ici *v1alpha1.ImageClusterInstall, message string) error {
+	return r.setClusterInstallingConditionsWithReason(ctx, ici, v1alpha1.InstallInProgressReason, message)
+}
+
+// setClusterInstallingConditionsWithReason sets the conditions of an installation in progress, reason tells why it
+// isn't completed yet
+func (r *ImageClusterInstallMonitor) setClusterInstallingConditionsWithReason(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	reason, message string) error {
+
 	patch := client.MergeFrom(ici.DeepCopy())
 	completedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
 		Type:    hivev1.ClusterInstallCompleted,
 		Status:  corev1.ConditionFalse,
-		Reason:  v1alpha1.InstallInProgressReason,
+		Reason:  reason,
 		Message: v1alpha1.InstallInProgressMessage,
 	})
 	stoppedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
 		Type:    hivev1.ClusterInstallStopped,
 		Status:  corev1.ConditionFalse,
-		Reason:  v1alpha1.InstallInProgressReason,
+		Reason:  reason,
 		Message: message,
 	})
 	failedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
@@ -157,25 +176,24 @@ func (r *ImageClusterInstallMonitor) setClusterInstallingConditions(ctx context.
 	return r.Status().Patch(ctx, ici, patch)
 }
 
-func (r *ImageClusterInstallMonitor) setClusterTimeoutConditions(ctx context.Context, ici *v1alpha1.ImageClusterInstall, timeout string) error {
-	message := fmt.Sprintf("Cluster failed to install within the timeout (%s)", timeout)
+func (r *ImageClusterInstallMonitor) setClusterTimeoutConditions(ctx context.Context, ici *v1alpha1.ImageClusterInstall, reason, message string) error {
 	patch := client.MergeFrom(ici.DeepCopy())
 	completedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
 		Type:    hivev1.ClusterInstallCompleted,
 		Status:  corev1.ConditionFalse,
-		Reason:  v1alpha1.InstallTimedoutReason,
+		Reason:  reason,
 		Message: message,
 	})
 	stoppedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
 		Type:    hivev1.ClusterInstallStopped,
 		Status:  corev1.ConditionFalse,
-		Reason:  v1alpha1.InstallTimedoutReason,
+		Reason:  reason,
 		Message: v1alpha1.InstallTimedoutMessage,
 	})
 	failedUpdated := setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
 		Type:    hivev1.ClusterInstallFailed,
 		Status:  corev1.ConditionTrue,
-		Reason:  v1alpha1.InstallTimedoutReason,
+		Reason:  reason,
 		Message: message,
 	})
 
@@ -0,0 +1,377 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	apicfgv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	// defaultedFieldsAnnotation lists the spec fields that were set by the operator rather than by the user
+	defaultedFieldsAnnotation = "imageclusterinstall." + v1alpha1.Group + "/defaulted-fields"
+
+	// keys of the deprecated hub-wide defaults ConfigMap
+	defaultsImageDigestSourcesKey = "imageDigestSources"
+	defaultsIPv4PrefixLengthKey   = "ipv4MachineNetworkPrefixLength"
+	defaultsIPv6PrefixLengthKey   = "ipv6MachineNetworkPrefixLength"
+	defaultsMergeGlobalPullSecret = "mergeGlobalPullSecret"
+	defaultsHubImageDigestMirrors = "useHubImageDigestMirrorSets"
+
+	// prefix lengths of the machine networks derived from NIC addresses, the inspection data has no netmask
+	defaultIPv4PrefixLength = 24
+	defaultIPv6PrefixLength = 64
+
+	hostnameField             = "hostname"
+	machineNetworksField      = "machineNetworks"
+	imageDigestSourcesField   = "imageDigestSources"
+	sshKeyField               = "sshKey"
+	additionalNTPSourcesField = "additionalNTPSources"
+	proxyField                = "proxy"
+)
+
+// hubDefaults is the hub-wide configuration read from the defaults of the ImageBasedInstallOperatorConfig
+type hubDefaults struct {
+	// imageDigestSources are the configured ones followed by the ones of the hub's ImageDigestMirrorSets when
+	// useHubImageDigestMirrorSets is set
+	imageDigestSources []apicfgv1.ImageDigestMirrors
+	ipv4PrefixLength   int
+	ipv6PrefixLength   int
+	// mergeGlobalPullSecret merges the hub's global pull secret into the pull secret of every ClusterDeployment
+	mergeGlobalPullSecret bool
+}
+
+// setDefaults sets the unset hostname, machineNetworks and imageDigestSources of the ImageClusterInstall from the
+// BareMetalHost and the hub-wide defaults, and its unset sshKey, additionalNTPSources and proxy from the operator
+// config. The values are written to the spec and the fields are listed in the
+// defaulted-fields annotation, so a reinstall from the same ImageClusterInstall reproduces them.
+func (r *ImageClusterInstallReconciler) setDefaults(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	bmh *bmh_v1alpha1.BareMetalHost) error {
+
+	patch := client.MergeFrom(ici.DeepCopy())
+	defaulted, err := r.applyDefaults(ctx, log, ici, bmh)
+	if err != nil || len(defaulted) == 0 {
+		return err
+	}
+
+	if previous := ici.Annotations[defaultedFieldsAnnotation]; previous != "" {
+		defaulted = append(defaulted, strings.Split(previous, ",")...)
+	}
+	if ici.Annotations == nil {
+		ici.Annotations = map[string]string{}
+	}
+	ici.Annotations[defaultedFieldsAnnotation] = strings.Join(uniqueSorted(defaulted), ",")
+
+	return r.Patch(ctx, ici, patch)
+}
+
+// applyDefaults sets the unset fields of ici in memory and returns the names of the fields it set
+func (r *ImageClusterInstallReconciler) applyDefaults(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	bmh *bmh_v1alpha1.BareMetalHost) ([]string, error) {
+
+	defaults, err := r.getHubDefaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nmstateConfig *nmstateNetworkConfig
+	if ici.Spec.Hostname == "" || !hasMachineNetworks(ici) {
+		nmstateConfig, err = r.networkConfig(ctx, ici, bmh)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defaulted := []string{}
+	if ici.Spec.Hostname == "" && bmh != nil {
+		if hostname := defaultHostname(bmh, nmstateConfig); hostname != "" {
+			log.Infof("Defaulting hostname to %s", hostname)
+			ici.Spec.Hostname = hostname
+			defaulted = append(defaulted, hostnameField)
+		}
+	}
+
+	if !hasMachineNetworks(ici) {
+		networks := machineNetworksFromNMState(nmstateConfig)
+		if len(networks) == 0 && bmh != nil {
+			networks = machineNetworksFromNICs(bmh, defaults.ipv4PrefixLength, defaults.ipv6PrefixLength)
+		}
+		if len(networks) > 0 {
+			log.Infof("Defaulting machineNetworks to %v", networks)
+			for _, network := range networks {
+				ici.Spec.MachineNetworks = append(ici.Spec.MachineNetworks, v1alpha1.MachineNetworkEntry{CIDR: network})
+			}
+			defaulted = append(defaulted, machineNetworksField)
+		}
+	}
+
+	if len(ici.Spec.ImageDigestSources) == 0 && len(defaults.imageDigestSources) > 0 {
+		log.Info("Defaulting imageDigestSources from the hub defaults")
+		ici.Spec.ImageDigestSources = defaults.imageDigestSources
+		defaulted = append(defaulted, imageDigestSourcesField)
+	} else if ici.Spec.InheritImageDigestSources && mergeImageDigestSources(ici, defaults.imageDigestSources) {
+		log.Info("Adding the hub defaults to imageDigestSources")
+		defaulted = append(defaulted, imageDigestSourcesField)
+	}
+
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		return nil, err
+	}
+	configDefaults := config.defaults()
+	if ici.Spec.SSHKey == "" && ici.Spec.SSHKeysSecretRef == nil && configDefaults.SSHKey != "" {
+		log.Info("Defaulting sshKey from the operator config")
+		ici.Spec.SSHKey = configDefaults.SSHKey
+		defaulted = append(defaulted, sshKeyField)
+	}
+	if len(ici.Spec.AdditionalNTPSources) == 0 && len(configDefaults.AdditionalNTPSources) > 0 {
+		log.Infof("Defaulting additionalNTPSources to %v", configDefaults.AdditionalNTPSources)
+		ici.Spec.AdditionalNTPSources = append([]string{}, configDefaults.AdditionalNTPSources...)
+		defaulted = append(defaulted, additionalNTPSourcesField)
+	}
+	if ici.Spec.Proxy == nil && configDefaults.Proxy != nil {
+		log.Info("Defaulting proxy from the operator config")
+		ici.Spec.Proxy = &v1alpha1.Proxy{
+			HTTPProxy:  configDefaults.Proxy.HTTPProxy,
+			HTTPSProxy: configDefaults.Proxy.HTTPSProxy,
+			NoProxy:    configDefaults.Proxy.NoProxy,
+		}
+		defaulted = append(defaulted, proxyField)
+	}
+
+	return defaulted, nil
+}
+
+// getHubDefaults returns the hub-wide defaults of the operator config. The fields it leaves unset are read from the
+// deprecated defaults ConfigMap.
+func (r *ImageClusterInstallReconciler) getHubDefaults(ctx context.Context) (*hubDefaults, error) {
+	defaults := &hubDefaults{
+		ipv4PrefixLength: defaultIPv4PrefixLength,
+		ipv6PrefixLength: defaultIPv6PrefixLength,
+	}
+	useHubMirrors, err := r.getConfigMapHubDefaults(ctx, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := getOperatorConfig(ctx, r.Client, r.Log)
+	if err != nil {
+		return nil, err
+	}
+	configDefaults := config.defaults()
+	if len(configDefaults.ImageDigestSources) > 0 {
+		defaults.imageDigestSources = configDefaults.ImageDigestSources
+	}
+	if configDefaults.UseHubImageDigestMirrorSets != nil {
+		useHubMirrors = *configDefaults.UseHubImageDigestMirrorSets
+	}
+	if configDefaults.MergeGlobalPullSecret != nil {
+		defaults.mergeGlobalPullSecret = *configDefaults.MergeGlobalPullSecret
+	}
+	if configDefaults.IPv4MachineNetworkPrefixLength != nil {
+		defaults.ipv4PrefixLength = int(*configDefaults.IPv4MachineNetworkPrefixLength)
+	}
+	if configDefaults.IPv6MachineNetworkPrefixLength != nil {
+		defaults.ipv6PrefixLength = int(*configDefaults.IPv6MachineNetworkPrefixLength)
+	}
+
+	if useHubMirrors {
+		hubMirrors, err := r.hubImageDigestMirrors(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defaults.imageDigestSources = append(append([]apicfgv1.ImageDigestMirrors{}, defaults.imageDigestSources...), hubMirrors...)
+	}
+	return defaults, nil
+}
+
+// getConfigMapHubDefaults sets the defaults from the ConfigMap named by the DEFAULTS_CONFIGMAP option, and returns
+// whether it enables useHubImageDigestMirrorSets. The ConfigMap is deprecated in favor of the operator config.
+func (r *ImageClusterInstallReconciler) getConfigMapHubDefaults(ctx context.Context, defaults *hubDefaults) (bool, error) {
+	if r.Options == nil || r.Options.DefaultsConfigMap == "" {
+		return false, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: r.Options.DefaultsConfigMap, Namespace: r.Options.ServiceNamespace}
+	if err := r.Get(ctx, key, cm); err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get defaults ConfigMap %s: %w", key, err)
+	}
+	if len(cm.Data) > 0 {
+		r.Log.Warnf("The defaults ConfigMap %s is deprecated, set the defaults in the ImageBasedInstallOperatorConfig %s",
+			key, v1alpha1.OperatorConfigName)
+	}
+
+	if value, present := cm.Data[defaultsImageDigestSourcesKey]; present {
+		if err := yaml.Unmarshal([]byte(value), &defaults.imageDigestSources); err != nil {
+			return false, fmt.Errorf("failed to parse %s in defaults ConfigMap %s: %w", defaultsImageDigestSourcesKey, key, err)
+		}
+	}
+	if value, present := cm.Data[defaultsMergeGlobalPullSecret]; present {
+		merge, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s %q in defaults ConfigMap %s", defaultsMergeGlobalPullSecret, value, key)
+		}
+		defaults.mergeGlobalPullSecret = merge
+	}
+	useHubMirrors := false
+	if value, present := cm.Data[defaultsHubImageDigestMirrors]; present {
+		var err error
+		useHubMirrors, err = strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s %q in defaults ConfigMap %s", defaultsHubImageDigestMirrors, value, key)
+		}
+	}
+	for dataKey, prefixLength := range map[string]*int{
+		defaultsIPv4PrefixLengthKey: &defaults.ipv4PrefixLength,
+		defaultsIPv6PrefixLengthKey: &defaults.ipv6PrefixLength,
+	} {
+		value, present := cm.Data[dataKey]
+		if !present {
+			continue
+		}
+		length, err := strconv.Atoi(value)
+		if err != nil || length <= 0 {
+			return false, fmt.Errorf("invalid %s %q in defaults ConfigMap %s", dataKey, value, key)
+		}
+		*prefixLength = length
+	}
+
+	return useHubMirrors, nil
+}
+
+func hasMachineNetworks(ici *v1alpha1.ImageClusterInstall) bool {
+	return ici.Spec.MachineNetwork != "" || len(ici.Spec.MachineNetworks) > 0
+}
+
+// defaultHostname returns the nmstate hostname of the host, or its name when it is a valid hostname
+func defaultHostname(bmh *bmh_v1alpha1.BareMetalHost, config *nmstateNetworkConfig) string {
+	candidates := []string{bmh.Name}
+	if config != nil {
+		candidates = []string{config.Hostname.Config, config.Hostname.Running, bmh.Name}
+	}
+	for _, hostname := range candidates {
+		if hostname != "" && len(validation.IsDNS1123Subdomain(hostname)) == 0 {
+			return hostname
+		}
+	}
+	return ""
+}
+
+// machineNetworksFromNMState returns the networks of the first static IPv4 and IPv6 addresses, IPv4 first
+func machineNetworksFromNMState(config *nmstateNetworkConfig) []string {
+	if config == nil {
+		return nil
+	}
+	var ipv4, ipv6 string
+	for _, iface := range config.Interfaces {
+		for _, ipConfig := range []nmstateIPConfig{iface.IPv4, iface.IPv6} {
+			for _, address := range ipConfig.Address {
+				ip := net.ParseIP(address.IP)
+				if !usableIP(ip) {
+					continue
+				}
+				network := networkCIDR(ip, address.PrefixLength.IntValue())
+				if network == "" {
+					continue
+				}
+				if ip.To4() != nil && ipv4 == "" {
+					ipv4 = network
+				} else if ip.To4() == nil && ipv6 == "" {
+					ipv6 = network
+				}
+			}
+		}
+	}
+	return nonEmpty(ipv4, ipv6)
+}
+
+// machineNetworksFromNICs returns the networks of the first IPv4 and IPv6 addresses of the boot NIC, or of any NIC
+// when the boot MAC address is unset, IPv4 first
+func machineNetworksFromNICs(bmh *bmh_v1alpha1.BareMetalHost, ipv4PrefixLength, ipv6PrefixLength int) []string {
+	if bmh.Status.HardwareDetails == nil {
+		return nil
+	}
+	var ipv4, ipv6 string
+	for _, nic := range bmh.Status.HardwareDetails.NIC {
+		if bmh.Spec.BootMACAddress != "" && !strings.EqualFold(nic.MAC, bmh.Spec.BootMACAddress) {
+			continue
+		}
+		ip := net.ParseIP(nic.IP)
+		if !usableIP(ip) {
+			continue
+		}
+		if ip.To4() != nil && ipv4 == "" {
+			ipv4 = networkCIDR(ip, ipv4PrefixLength)
+		} else if ip.To4() == nil && ipv6 == "" {
+			ipv6 = networkCIDR(ip, ipv6PrefixLength)
+		}
+	}
+	return nonEmpty(ipv4, ipv6)
+}
+
+func usableIP(ip net.IP) bool {
+	return ip != nil && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
+
+// networkCIDR returns the network of ip with the given prefix length, or an empty string when the length is invalid
+func networkCIDR(ip net.IP, prefixLength int) string {
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+	mask := net.CIDRMask(prefixLength, bits)
+	if mask == nil {
+		return ""
+	}
+	network := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+	return network.String()
+}
+
+func nonEmpty(values ...string) []string {
+	result := []string{}
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func uniqueSorted(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
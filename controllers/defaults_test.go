@@ -0,0 +1,287 @@
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	apicfgv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+var _ = Describe("setDefaults", func() {
+	var (
+		c   client.Client
+		r   *ImageClusterInstallReconciler
+		ctx = context.Background()
+		ici *v1alpha1.ImageClusterInstall
+		bmh *bmh_v1alpha1.BareMetalHost
+	)
+
+	BeforeEach(func() {
+		c = fakeclient.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		r = &ImageClusterInstallReconciler{
+			Client:          c,
+			NoncachedClient: c,
+			Scheme:          scheme.Scheme,
+			Log:             logrus.New(),
+			Options: &ImageClusterInstallReconcilerOptions{
+				ServiceNamespace:  "operator-namespace",
+				DefaultsConfigMap: "defaults",
+			},
+		}
+
+		ici = &v1alpha1.ImageClusterInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "ici", Namespace: "test-namespace"},
+		}
+		Expect(c.Create(ctx, ici)).To(Succeed())
+
+		bmh = &bmh_v1alpha1.BareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "host-0", Namespace: "test-namespace"},
+			Spec:       bmh_v1alpha1.BareMetalHostSpec{BootMACAddress: "52:54:00:8a:88:a8"},
+			Status: bmh_v1alpha1.BareMetalHostStatus{
+				HardwareDetails: &bmh_v1alpha1.HardwareDetails{NIC: []bmh_v1alpha1.NIC{
+					{MAC: "52:54:00:00:00:01", IP: "198.51.100.7"},
+					{MAC: "52:54:00:8A:88:A8", IP: "fe80::1"},
+					{MAC: "52:54:00:8A:88:A8", IP: "192.0.2.10"},
+					{MAC: "52:54:00:8A:88:A8", IP: "2001:db8::10"},
+				}},
+			},
+		}
+	})
+
+	addNetworkConfig := func(nmstate string) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "host-0-network", Namespace: bmh.Namespace},
+			Data:       map[string][]byte{nmstateSecretKey: []byte(nmstate)},
+		}
+		Expect(c.Create(ctx, secret)).To(Succeed())
+		bmh.Spec.PreprovisioningNetworkDataName = secret.Name
+	}
+
+	addHubDefaults := func(data map[string]string) {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "operator-namespace"},
+			Data:       data,
+		}
+		Expect(c.Create(ctx, cm)).To(Succeed())
+	}
+
+	It("defaults the hostname and machine networks from the host NICs and records them", func() {
+		Expect(r.setDefaults(ctx, r.Log, ici, bmh)).To(Succeed())
+
+		updated := &v1alpha1.ImageClusterInstall{}
+		Expect(c.Get(ctx, types.NamespacedName{Name: ici.Name, Namespace: ici.Namespace}, updated)).To(Succeed())
+		Expect(updated.Spec.Hostname).To(Equal("host-0"))
+		Expect(updated.Spec.MachineNetworks).To(Equal([]v1alpha1.MachineNetworkEntry{{CIDR: "192.0.2.0/24"}, {CIDR: "2001:db8::/64"}}))
+		Expect(updated.Annotations).To(HaveKeyWithValue(defaultedFieldsAnnotation, "hostname,machineNetworks"))
+	})
+
+	It("uses the prefix lengths from the hub defaults for NIC addresses", func() {
+		addHubDefaults(map[string]string{defaultsIPv4PrefixLengthKey: "16", defaultsIPv6PrefixLengthKey: "48"})
+
+		_, err := r.applyDefaults(ctx, r.Log, ici, bmh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ici.Spec.MachineNetworks).To(Equal([]v1alpha1.MachineNetworkEntry{{CIDR: "192.0.0.0/16"}, {CIDR: "2001:db8::/48"}}))
+	})
+
+	It("prefers the hostname and static addresses of the host network config", func() {
+		addNetworkConfig(`
+hostname:
+  config: node-0.example.com
+interfaces:
+  - name: enp1s0
+    ipv4:
+      enabled: true
+      address:
+        - ip: 203.0.113.20
+          prefix-length: "25"
+`)
+
+		defaulted, err := r.applyDefaults(ctx, r.Log, ici, bmh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulted).To(ConsistOf(hostnameField, machineNetworksField))
+		Expect(ici.Spec.Hostname).To(Equal("node-0.example.com"))
+		Expect(ici.Spec.MachineNetworks).To(Equal([]v1alpha1.MachineNetworkEntry{{CIDR: "203.0.113.0/25"}}))
+	})
+
+	It("defaults imageDigestSources from the hub defaults", func() {
+		addHubDefaults(map[string]string{defaultsImageDigestSourcesKey: `
+- source: quay.io/openshift-release-dev/ocp-release
+  mirrors:
+  - mirror.example.com/ocp-release
+`})
+
+		defaulted, err := r.applyDefaults(ctx, r.Log, ici, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulted).To(Equal([]string{imageDigestSourcesField}))
+		Expect(ici.Spec.ImageDigestSources).To(Equal([]apicfgv1.ImageDigestMirrors{{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/ocp-release"},
+		}}))
+	})
+
+	It("keeps the values set by the user", func() {
+		ici.Spec.Hostname = "custom"
+		ici.Spec.MachineNetwork = "198.51.100.0/24"
+		ici.Spec.ImageDigestSources = []apicfgv1.ImageDigestMirrors{{Source: "registry.example.com/release"}}
+		addHubDefaults(map[string]string{defaultsImageDigestSourcesKey: "- source: quay.io/openshift-release-dev/ocp-release"})
+
+		Expect(r.setDefaults(ctx, r.Log, ici, bmh)).To(Succeed())
+		Expect(ici.Spec.Hostname).To(Equal("custom"))
+		Expect(ici.Spec.MachineNetworks).To(BeEmpty())
+		Expect(ici.Spec.ImageDigestSources).To(Equal([]apicfgv1.ImageDigestMirrors{{Source: "registry.example.com/release"}}))
+		Expect(ici.Annotations).NotTo(HaveKey(defaultedFieldsAnnotation))
+	})
+
+	It("adds the image digest mirrors of the hub ImageDigestMirrorSets to the hub defaults", func() {
+		addHubDefaults(map[string]string{
+			defaultsImageDigestSourcesKey: "- source: quay.io/openshift-release-dev/ocp-release\n  mirrors:\n  - mirror.example.com/ocp-release",
+			defaultsHubImageDigestMirrors: "true",
+		})
+		for _, name := range []string{"b-mirrors", "a-mirrors"} {
+			Expect(c.Create(ctx, &apicfgv1.ImageDigestMirrorSet{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: apicfgv1.ImageDigestMirrorSetSpec{ImageDigestMirrors: []apicfgv1.ImageDigestMirrors{{
+					Source:  "registry.example.com/" + name,
+					Mirrors: []apicfgv1.ImageMirror{apicfgv1.ImageMirror("mirror.example.com/" + name)},
+				}}},
+			})).To(Succeed())
+		}
+
+		defaults, err := r.getHubDefaults(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaults.imageDigestSources).To(Equal([]apicfgv1.ImageDigestMirrors{
+			{Source: "quay.io/openshift-release-dev/ocp-release", Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/ocp-release"}},
+			{Source: "registry.example.com/a-mirrors", Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/a-mirrors"}},
+			{Source: "registry.example.com/b-mirrors", Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/b-mirrors"}},
+		}))
+	})
+
+	It("adds the hub imageDigestSources to the ones set by the user when the ImageClusterInstall inherits them", func() {
+		ici.Spec.Hostname = "custom"
+		ici.Spec.MachineNetwork = "198.51.100.0/24"
+		ici.Spec.InheritImageDigestSources = true
+		ici.Spec.ImageDigestSources = []apicfgv1.ImageDigestMirrors{{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []apicfgv1.ImageMirror{"site.example.com/ocp-release"},
+		}}
+		addHubDefaults(map[string]string{defaultsImageDigestSourcesKey: `
+- source: quay.io/openshift-release-dev/ocp-release
+  mirrors:
+  - mirror.example.com/ocp-release
+- source: quay.io/openshift-release-dev/ocp-v4.0-art-dev
+  mirrors:
+  - mirror.example.com/ocp-v4.0-art-dev`})
+
+		// setDefaults patches the stored object, the user values must be stored first
+		Expect(c.Update(ctx, ici)).To(Succeed())
+		Expect(r.setDefaults(ctx, r.Log, ici, bmh)).To(Succeed())
+		expected := []apicfgv1.ImageDigestMirrors{
+			{
+				Source:  "quay.io/openshift-release-dev/ocp-release",
+				Mirrors: []apicfgv1.ImageMirror{"site.example.com/ocp-release", "mirror.example.com/ocp-release"},
+			},
+			{
+				Source:  "quay.io/openshift-release-dev/ocp-v4.0-art-dev",
+				Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/ocp-v4.0-art-dev"},
+			},
+		}
+		Expect(ici.Spec.ImageDigestSources).To(Equal(expected))
+		Expect(ici.Annotations).To(HaveKeyWithValue(defaultedFieldsAnnotation, imageDigestSourcesField))
+
+		// merging again changes nothing
+		defaulted, err := r.applyDefaults(ctx, r.Log, ici, bmh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulted).To(BeEmpty())
+		Expect(ici.Spec.ImageDigestSources).To(Equal(expected))
+	})
+
+	It("defaults the sshKey, additionalNTPSources and proxy from the operator config", func() {
+		ici.Spec.Hostname = "custom"
+		ici.Spec.MachineNetwork = "198.51.100.0/24"
+		ici.Spec.AdditionalNTPSources = []string{"ntp.site.example.com"}
+		Expect(c.Create(ctx, &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{Defaults: &v1alpha1.InstallDefaults{
+				SSHKey:               "ssh-ed25519 AAAA default",
+				AdditionalNTPSources: []string{"ntp.example.com"},
+				Proxy:                &v1alpha1.DefaultProxy{HTTPProxy: "http://proxy.example.com:3128", NoProxy: ".example.com"},
+			}},
+		})).To(Succeed())
+
+		// setDefaults patches the stored object, the user values must be stored first
+		Expect(c.Update(ctx, ici)).To(Succeed())
+		Expect(r.setDefaults(ctx, r.Log, ici, bmh)).To(Succeed())
+		Expect(ici.Spec.SSHKey).To(Equal("ssh-ed25519 AAAA default"))
+		Expect(ici.Spec.AdditionalNTPSources).To(Equal([]string{"ntp.site.example.com"}))
+		Expect(ici.Spec.Proxy).To(Equal(&v1alpha1.Proxy{HTTPProxy: "http://proxy.example.com:3128", NoProxy: ".example.com"}))
+		Expect(ici.Annotations).To(HaveKeyWithValue(defaultedFieldsAnnotation, "proxy,sshKey"))
+	})
+
+	It("prefers the hub defaults of the operator config to the defaults ConfigMap", func() {
+		addHubDefaults(map[string]string{
+			defaultsImageDigestSourcesKey: "- source: quay.io/openshift-release-dev/ocp-release",
+			defaultsIPv4PrefixLengthKey:   "16",
+			defaultsMergeGlobalPullSecret: "true",
+		})
+		Expect(c.Create(ctx, &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{Defaults: &v1alpha1.InstallDefaults{
+				ImageDigestSources: []apicfgv1.ImageDigestMirrors{{
+					Source:  "quay.io/openshift-release-dev/ocp-release",
+					Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/ocp-release"},
+				}},
+				MergeGlobalPullSecret:          ptr.To(false),
+				IPv6MachineNetworkPrefixLength: ptr.To[int32](48),
+			}},
+		})).To(Succeed())
+
+		defaults, err := r.getHubDefaults(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaults.imageDigestSources).To(Equal([]apicfgv1.ImageDigestMirrors{{
+			Source:  "quay.io/openshift-release-dev/ocp-release",
+			Mirrors: []apicfgv1.ImageMirror{"mirror.example.com/ocp-release"},
+		}}))
+		Expect(defaults.mergeGlobalPullSecret).To(BeFalse())
+		// the fields the operator config leaves unset still come from the ConfigMap
+		Expect(defaults.ipv4PrefixLength).To(Equal(16))
+		Expect(defaults.ipv6PrefixLength).To(Equal(48))
+	})
+
+	It("ignores an invalid operator config", func() {
+		ici.Spec.Hostname = "custom"
+		ici.Spec.MachineNetwork = "198.51.100.0/24"
+		Expect(c.Create(ctx, &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{
+				Defaults:       &v1alpha1.InstallDefaults{SSHKey: "ssh-ed25519 AAAA default"},
+				ImageServerURL: "images.example.com",
+			},
+		})).To(Succeed())
+
+		defaulted, err := r.applyDefaults(ctx, r.Log, ici, bmh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulted).To(BeEmpty())
+		Expect(ici.Spec.SSHKey).To(BeEmpty())
+	})
+
+	It("fails on invalid hub defaults", func() {
+		addHubDefaults(map[string]string{defaultsIPv4PrefixLengthKey: "wide"})
+
+		_, err := r.applyDefaults(ctx, r.Log, ici, bmh)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+// maxDiagnosticsSize bounds the size of the diagnostics ConfigMap data, well below the 1MiB limit of an object
+const maxDiagnosticsSize = 512 * 1024
+
+const truncatedDiagnosticsMarker = "\n... truncated\n"
+
+func DiagnosticsConfigMapName(ici *v1alpha1.ImageClusterInstall) string {
+	return ici.Name + "-diagnostics"
+}
+
+// boundDiagnostics truncates the diagnostics so their total size stays under limit. The limit is shared evenly, the
+// part the smaller diagnostics don't use goes to the larger ones. Diagnostics are truncated on a character boundary.
+// It returns true when some were truncated.
+func boundDiagnostics(diagnostics map[string]string, limit int) (map[string]string, bool) {
+	keys := make([]string, 0, len(diagnostics))
+	for key := range diagnostics {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(diagnostics[keys[i]]) != len(diagnostics[keys[j]]) {
+			return len(diagnostics[keys[i]]) < len(diagnostics[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	bounded := make(map[string]string, len(diagnostics))
+	truncated := false
+	remaining := limit
+	for i, key := range keys {
+		value := diagnostics[key]
+		share := remaining / (len(keys) - i)
+		if len(value) > share {
+			cut := max(share-len(truncatedDiagnosticsMarker), 0)
+			// don't split a multi-byte character
+			for cut > 0 && !utf8.RuneStart(value[cut]) {
+				cut--
+			}
+			value = value[:cut] + truncatedDiagnosticsMarker
+			truncated = true
+		}
+		bounded[key] = value
+		remaining -= len(value)
+	}
+	return bounded, truncated
+}
+
+// collectDiagnostics stores the diagnostics of the cluster in a ConfigMap owned by the ImageClusterInstall once its
+// installation failed, and references it in the status. The diagnostics are collected once per installation attempt.
+// Collection is best-effort, errors are only logged so they never change the outcome of the installation.
+func (r *ImageClusterInstallMonitor) collectDiagnostics(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	spokeClient client.Client) {
+
+	failed := findCondition(ici.Status.Conditions, hivev1.ClusterInstallFailed)
+	if failed == nil || failed.Status != corev1.ConditionTrue {
+		return
+	}
+	if ici.Status.Diagnostics != nil && !ici.Status.Diagnostics.CollectionTime.Before(&ici.Status.BootTime) {
+		return
+	}
+
+	log.Infof("Collecting diagnostics from the cluster after %s", failed.Reason)
+	data, truncated := boundDiagnostics(r.CollectSpokeDiagnostics(ctx, log, spokeClient), maxDiagnosticsSize)
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DiagnosticsConfigMapName(ici),
+			Namespace: ici.Namespace,
+		},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = data
+		return controllerutil.SetControllerReference(ici, cm, r.Scheme)
+	})
+	if err != nil {
+		log.WithError(err).Warnf("failed to write diagnostics ConfigMap %s/%s", cm.Namespace, cm.Name)
+		return
+	}
+	log.Infof("Diagnostics ConfigMap %s/%s %s", cm.Namespace, cm.Name, op)
+
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Status.Diagnostics = &v1alpha1.ClusterDiagnostics{
+		ConfigMapRef:   corev1.LocalObjectReference{Name: cm.Name},
+		Reason:         failed.Reason,
+		CollectionTime: metav1.Now(),
+		Truncated:      truncated,
+	}
+	if err := r.Status().Patch(ctx, ici, patch); err != nil {
+		log.WithError(err).Warn("failed to reference the diagnostics in the status")
+	}
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("boundDiagnostics", func() {
+	It("keeps diagnostics within the limit", func() {
+		diagnostics := map[string]string{"small.yaml": "small", "large.yaml": "large"}
+		bounded, truncated := boundDiagnostics(diagnostics, 1024)
+		Expect(truncated).To(BeFalse())
+		Expect(bounded).To(Equal(diagnostics))
+	})
+
+	It("gives the space the small diagnostics don't use to the large ones", func() {
+		diagnostics := map[string]string{
+			"small.yaml":  strings.Repeat("s", 100),
+			"large.yaml":  strings.Repeat("l", 2000),
+			"larger.yaml": strings.Repeat("L", 3000),
+		}
+		bounded, truncated := boundDiagnostics(diagnostics, 1000)
+		Expect(truncated).To(BeTrue())
+		Expect(bounded["small.yaml"]).To(Equal(diagnostics["small.yaml"]))
+		Expect(bounded["large.yaml"]).To(HaveLen(450))
+		Expect(bounded["large.yaml"]).To(HaveSuffix(truncatedDiagnosticsMarker))
+		Expect(bounded["larger.yaml"]).To(HaveLen(450))
+		Expect(bounded["larger.yaml"]).To(HavePrefix("LLL"))
+	})
+
+	It("doesn't split multi-byte characters", func() {
+		diagnostics := map[string]string{"events.yaml": strings.Repeat("é", 1000)}
+		bounded, truncated := boundDiagnostics(diagnostics, 100+len(truncatedDiagnosticsMarker))
+		Expect(truncated).To(BeTrue())
+		Expect(utf8.ValidString(bounded["events.yaml"])).To(BeTrue())
+		Expect(bounded["events.yaml"]).To(Equal(strings.Repeat("é", 50) + truncatedDiagnosticsMarker))
+
+		bounded, _ = boundDiagnostics(diagnostics, 101+len(truncatedDiagnosticsMarker))
+		Expect(bounded["events.yaml"]).To(Equal(strings.Repeat("é", 50) + truncatedDiagnosticsMarker))
+	})
+})
@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/internal/monitor"
+)
+
+// extraManifest is an extra manifest file and the object it was read from
+type extraManifest struct {
+	name    string
+	source  string
+	content []byte
+	// sensitive manifests are read from Secrets and are only readable by the operator
+	sensitive bool
+}
+
+// reservedExtraManifestNames are the names of the extra manifests written by the operator
+var reservedExtraManifestNames = []string{invokerCMFileName, monitor.IBIOStartTimeCM + ".yaml"}
+
+// extraManifestsTemplateData is what the templated extra manifests are rendered with
+type extraManifestsTemplateData struct {
+	ImageClusterInstall struct {
+		Name      string
+		Namespace string
+		Spec      v1alpha1.ImageClusterInstallSpec
+	}
+	ClusterDeployment struct {
+		ClusterName string
+		BaseDomain  string
+	}
+	BareMetalHost struct {
+		Name      string
+		Namespace string
+		Labels    map[string]string
+		NICs      []extraManifestsTemplateNIC
+	}
+	// Values are the extraManifestsValues of the ImageClusterInstall
+	Values map[string]string
+}
+
+type extraManifestsTemplateNIC struct {
+	Name string
+	MAC  string
+}
+
+// newExtraManifestsTemplateData builds the template data, the host fields are empty when there is no BareMetalHost
+func newExtraManifestsTemplateData(
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost) *extraManifestsTemplateData {
+
+	data := &extraManifestsTemplateData{Values: ici.Spec.ExtraManifestsValues}
+	data.ImageClusterInstall.Name = ici.Name
+	data.ImageClusterInstall.Namespace = ici.Namespace
+	data.ImageClusterInstall.Spec = ici.Spec
+	if cd != nil {
+		data.ClusterDeployment.ClusterName = cd.Spec.ClusterName
+		data.ClusterDeployment.BaseDomain = cd.Spec.BaseDomain
+	}
+	if bmh != nil {
+		data.BareMetalHost.Name = bmh.Name
+		data.BareMetalHost.Namespace = bmh.Namespace
+		data.BareMetalHost.Labels = bmh.Labels
+		if bmh.Status.HardwareDetails != nil {
+			for _, nic := range bmh.Status.HardwareDetails.NIC {
+				data.BareMetalHost.NICs = append(data.BareMetalHost.NICs, extraManifestsTemplateNIC{Name: nic.Name, MAC: nic.MAC})
+			}
+		}
+	}
+	return data
+}
+
+// renderExtraManifestTemplate renders a templated extra manifest, referencing a missing value is an error
+func renderExtraManifestTemplate(name string, content []byte, data *extraManifestsTemplateData) ([]byte, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// extraManifests reads the extra manifests from the ConfigMaps and Secrets referenced by the ImageClusterInstall,
+// renders the templated ones and validates them, the manifests of each object are sorted by name
+func (r *ImageClusterInstallReconciler) extraManifests(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost) ([]extraManifest, error) {
+
+	manifests := []extraManifest{}
+	renderErrs := []error{}
+	data := newExtraManifestsTemplateData(ici, cd, bmh)
+	for _, ref := range ici.Spec.ExtraManifestsRefs {
+		cm := &corev1.ConfigMap{}
+		key := types.NamespacedName{Name: ref.Name, Namespace: ici.Namespace}
+		if err := r.Get(ctx, key, cm); err != nil {
+			return nil, fmt.Errorf("failed to get extraManifests config map %w", err)
+		}
+
+		source := fmt.Sprintf("ConfigMap %s", cm.Name)
+		objectManifests := []extraManifest{}
+		for name, content := range cm.Data {
+			objectManifests = append(objectManifests, extraManifest{name: name, source: source, content: []byte(content)})
+		}
+		for name, content := range cm.BinaryData {
+			objectManifests = append(objectManifests, extraManifest{name: name, source: source, content: content})
+		}
+		if v1alpha1.IsExtraManifestsTemplate(cm) {
+			rendered := []extraManifest{}
+			for _, manifest := range objectManifests {
+				content, err := renderExtraManifestTemplate(manifest.name, manifest.content, data)
+				if err != nil {
+					renderErrs = append(renderErrs, fmt.Errorf("failed to render extra manifest template %s in %s: %w", manifest.name, source, err))
+					continue
+				}
+				manifest.content = content
+				rendered = append(rendered, manifest)
+			}
+			objectManifests = rendered
+		}
+		manifests = append(manifests, sortedExtraManifests(objectManifests)...)
+	}
+
+	for _, ref := range ici.Spec.ExtraManifestsSecretRefs {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Name: ref.Name, Namespace: ici.Namespace}
+		if err := r.NoncachedClient.Get(ctx, key, secret); err != nil {
+			return nil, fmt.Errorf("failed to get extraManifests secret %w", err)
+		}
+
+		source := fmt.Sprintf("Secret %s", secret.Name)
+		objectManifests := []extraManifest{}
+		for name, content := range secret.Data {
+			objectManifests = append(objectManifests, extraManifest{name: name, source: source, content: content, sensitive: true})
+		}
+		manifests = append(manifests, sortedExtraManifests(objectManifests)...)
+	}
+
+	if err := validateExtraManifests(manifests); err != nil {
+		renderErrs = append(renderErrs, err)
+	}
+	if err := k8serrors.NewAggregate(renderErrs); err != nil {
+		return nil, err
+	}
+	return manifests, nil
+}
+
+func sortedExtraManifests(manifests []extraManifest) []extraManifest {
+	sort.Slice(manifests, func(i, j int) bool { return manifests[i].name < manifests[j].name })
+	return manifests
+}
+
+// validateExtraManifests checks that every document of the manifests is a Kubernetes object, and that no two
+// manifests, nor a manifest and one written by the operator, have the same name
+func validateExtraManifests(manifests []extraManifest) error {
+	errs := []error{}
+	sources := map[string]string{}
+	for _, name := range reservedExtraManifestNames {
+		sources[name] = ""
+	}
+	for _, manifest := range manifests {
+		if source, found := sources[manifest.name]; found {
+			if source == "" {
+				errs = append(errs, fmt.Errorf("extra manifest %s in %s uses a name reserved by the operator", manifest.name, manifest.source))
+			} else {
+				errs = append(errs, fmt.Errorf("extra manifest %s in %s collides with the one in %s", manifest.name, manifest.source, source))
+			}
+		} else {
+			sources[manifest.name] = manifest.source
+		}
+
+		if err := v1alpha1.ValidateExtraManifest(manifest.content); err != nil {
+			errs = append(errs, fmt.Errorf("invalid extra manifest %s in %s: %w", manifest.name, manifest.source, err))
+		}
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+// writeExtraManifests writes the extra manifests to dir
+func writeExtraManifests(dir string, manifests []extraManifest) error {
+	for _, manifest := range manifests {
+		mode := os.FileMode(0644)
+		if manifest.sensitive {
+			mode = 0600
+		}
+		if err := os.WriteFile(filepath.Join(dir, manifest.name), manifest.content, mode); err != nil {
+			return fmt.Errorf("failed to write extra manifest file: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkExtraManifests validates the extra manifests and evaluates them against the ExtraManifestPolicy, the
+// violations are recorded in the ImageClusterInstall status and fail the reconcile when the policy is enforced
+func (r *ImageClusterInstallReconciler) checkExtraManifests(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	cond *hivev1.ClusterInstallCondition) error {
+
+	manifests, err := r.extraManifests(ctx, ici, cd, bmh)
+	if err != nil {
+		cond.Reason = v1alpha1.ExtraManifestsInvalidReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return err
+	}
+
+	violations, enforced, err := r.extraManifestPolicyViolations(ctx, manifests)
+	if err != nil {
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = "failed to get the ExtraManifestPolicy"
+		log.Error(err)
+		return err
+	}
+	if err := r.setExtraManifestPolicyViolations(ctx, ici, violations); err != nil {
+		log.WithError(err).Error("failed to record the extra manifest policy violations")
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if !enforced {
+		log.Warnf("extra manifests violate the audited ExtraManifestPolicy: %s", strings.Join(violations, "; "))
+		return nil
+	}
+	err = extraManifestPolicyError(violations)
+	cond.Reason = v1alpha1.ConfigurationFailedReason
+	cond.Message = err.Error()
+	log.Error(err)
+	return err
+}
+
+// extraManifestPolicyViolations evaluates the manifests against the ExtraManifestPolicy, it returns the violations
+// and whether the policy is enforced. There are no violations when there is no policy.
+func (r *ImageClusterInstallReconciler) extraManifestPolicyViolations(ctx context.Context, manifests []extraManifest) ([]string, bool, error) {
+	policy := &v1alpha1.ExtraManifestPolicy{}
+	if err := r.Get(ctx, types.NamespacedName{Name: v1alpha1.ExtraManifestPolicyName}, policy); err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get ExtraManifestPolicy %s: %w", v1alpha1.ExtraManifestPolicyName, err)
+	}
+
+	enforced := policy.Spec.Mode != v1alpha1.ExtraManifestPolicyModeAudit
+	return evaluateExtraManifestPolicy(&policy.Spec, manifests), enforced, nil
+}
+
+func extraManifestPolicyError(violations []string) error {
+	return fmt.Errorf("extra manifests violate the ExtraManifestPolicy: %s", strings.Join(violations, "; "))
+}
+
+func (r *ImageClusterInstallReconciler) setExtraManifestPolicyViolations(ctx context.Context, ici *v1alpha1.ImageClusterInstall, violations []string) error {
+	if slices.Equal(ici.Status.ExtraManifestPolicyViolations, violations) {
+		return nil
+	}
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Status.ExtraManifestPolicyViolations = violations
+	return r.Status().Patch(ctx, ici, patch)
+}
+
+// mapExtraManifestPolicyToICIs requeues the ImageClusterInstalls whose installation hasn't started when the
+// ExtraManifestPolicy changes
+func (r *ImageClusterInstallReconciler) mapExtraManifestPolicyToICIs(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != v1alpha1.ExtraManifestPolicyName {
+		return []reconcile.Request{}
+	}
+	iciList := &v1alpha1.ImageClusterInstallList{}
+	if err := r.List(ctx, iciList); err != nil {
+		r.Log.WithError(err).Error("failed to list ImageClusterInstalls for the ExtraManifestPolicy")
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, ici := range iciList.Items {
+		if ici.Status.BootTime.IsZero() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: ici.Namespace, Name: ici.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// evaluateExtraManifestPolicy returns the violations of the policy by the objects in the manifests
+func evaluateExtraManifestPolicy(policy *v1alpha1.ExtraManifestPolicySpec, manifests []extraManifest) []string {
+	var violations []string
+	for _, manifest := range manifests {
+		if policy.MaxManifestSize != nil && int64(len(manifest.content)) > policy.MaxManifestSize.Value() {
+			violations = append(violations, fmt.Sprintf("extra manifest %s in %s is %d bytes, larger than the %s limit",
+				manifest.name, manifest.source, len(manifest.content), policy.MaxManifestSize))
+		}
+
+		objects, err := manifestObjects(manifest.content)
+		if err != nil {
+			// invalid manifests are reported by validateExtraManifests
+			continue
+		}
+		for _, obj := range objects {
+			for _, violation := range objectPolicyViolations(policy, obj) {
+				violations = append(violations, fmt.Sprintf("extra manifest %s in %s: %s %s %s",
+					manifest.name, manifest.source, obj.GroupVersionKind().GroupKind(), obj.GetName(), violation))
+			}
+		}
+	}
+	return violations
+}
+
+func objectPolicyViolations(policy *v1alpha1.ExtraManifestPolicySpec, obj *unstructured.Unstructured) []string {
+	var violations []string
+	gvk := obj.GroupVersionKind()
+	matchesKind := func(kind v1alpha1.ManifestKind) bool {
+		return globMatch(kind.Group, gvk.Group) &&
+			(kind.Version == "" || globMatch(kind.Version, gvk.Version)) &&
+			globMatch(kind.Kind, gvk.Kind)
+	}
+	if len(policy.AllowedKinds) > 0 && !slices.ContainsFunc(policy.AllowedKinds, matchesKind) {
+		violations = append(violations, "has a kind that is not allowed")
+	}
+	if slices.ContainsFunc(policy.DeniedKinds, matchesKind) {
+		violations = append(violations, "has a denied kind")
+	}
+
+	namespace := obj.GetNamespace()
+	if gvk.Group == "" && gvk.Kind == "Namespace" {
+		namespace = obj.GetName()
+	}
+	if namespace == "" {
+		return violations
+	}
+	matchesNamespace := func(pattern string) bool { return globMatch(pattern, namespace) }
+	if len(policy.AllowedNamespaces) > 0 && !slices.ContainsFunc(policy.AllowedNamespaces, matchesNamespace) {
+		violations = append(violations, fmt.Sprintf("is in namespace %s that is not allowed", namespace))
+	}
+	if slices.ContainsFunc(policy.DeniedNamespaces, matchesNamespace) {
+		violations = append(violations, fmt.Sprintf("is in denied namespace %s", namespace))
+	}
+	return violations
+}
+
+// globMatch reports whether value matches the shell glob pattern, invalid patterns match nothing
+func globMatch(pattern, value string) bool {
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// manifestObjects decodes the objects in the documents of a manifest, empty documents are skipped and the items of
+// Lists are returned in place of the Lists
+func manifestObjects(content []byte) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(obj.Object) > 0 {
+			expanded, err := expandListObjects(obj)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, expanded...)
+		}
+	}
+}
+
+// expandListObjects returns the items of a List, or of a kind ending in List such as ConfigMapList, and of the Lists
+// they contain, or the object itself when it isn't a List
+func expandListObjects(obj *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if !strings.HasSuffix(obj.GetKind(), "List") || !obj.IsList() {
+		return []*unstructured.Unstructured{obj}, nil
+	}
+	list, err := obj.ToList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the items of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	objects := []*unstructured.Unstructured{}
+	for i := range list.Items {
+		expanded, err := expandListObjects(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, expanded...)
+	}
+	return objects, nil
+}
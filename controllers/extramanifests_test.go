@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validateExtraManifests", func() {
+	manifest := []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: thing\n")
+
+	It("accepts manifests with distinct names", func() {
+		Expect(validateExtraManifests([]extraManifest{
+			{name: "one.yaml", source: "ConfigMap manifests", content: manifest},
+			{name: "two.yaml", source: "Secret manifests", content: manifest},
+		})).To(Succeed())
+	})
+
+	It("reports manifests with the same name", func() {
+		err := validateExtraManifests([]extraManifest{
+			{name: "one.yaml", source: "ConfigMap first", content: manifest},
+			{name: "one.yaml", source: "ConfigMap second", content: manifest},
+			{name: "one.yaml", source: "Secret third", content: manifest},
+		})
+		Expect(err).To(MatchError(ContainSubstring("extra manifest one.yaml in ConfigMap second collides with the one in ConfigMap first")))
+		Expect(err).To(MatchError(ContainSubstring("extra manifest one.yaml in Secret third collides with the one in ConfigMap first")))
+	})
+
+	It("reports manifests with the names of the operator manifests", func() {
+		err := validateExtraManifests([]extraManifest{
+			{name: invokerCMFileName, source: "ConfigMap manifests", content: manifest},
+			{name: "ibi-monitor-cm.yaml", source: "ConfigMap manifests", content: manifest},
+		})
+		Expect(err).To(MatchError(ContainSubstring("extra manifest invoker-cm.yaml in ConfigMap manifests uses a name reserved by the operator")))
+		Expect(err).To(MatchError(ContainSubstring("extra manifest ibi-monitor-cm.yaml in ConfigMap manifests uses a name reserved by the operator")))
+	})
+
+	It("reports invalid documents", func() {
+		err := validateExtraManifests([]extraManifest{
+			{name: "one.yaml", source: "ConfigMap manifests", content: []byte("kind: Namespace\n")},
+		})
+		Expect(err).To(MatchError(ContainSubstring("invalid extra manifest one.yaml in ConfigMap manifests: document 1 is missing apiVersion, metadata.name")))
+	})
+})
+
+var _ = Describe("renderExtraManifestTemplate", func() {
+	var data *extraManifestsTemplateData
+
+	BeforeEach(func() {
+		ici := &v1alpha1.ImageClusterInstall{
+			ObjectMeta: metav1.ObjectMeta{Name: "ici", Namespace: "site"},
+			Spec: v1alpha1.ImageClusterInstallSpec{
+				Hostname:             "host.example.com",
+				ExtraManifestsValues: map[string]string{"vlan": "100"},
+			},
+		}
+		cd := &hivev1.ClusterDeployment{Spec: hivev1.ClusterDeploymentSpec{ClusterName: "sno", BaseDomain: "example.com"}}
+		bmh := &bmh_v1alpha1.BareMetalHost{
+			ObjectMeta: metav1.ObjectMeta{Name: "host", Namespace: "hosts", Labels: map[string]string{"rack": "r1"}},
+			Status: bmh_v1alpha1.BareMetalHostStatus{
+				HardwareDetails: &bmh_v1alpha1.HardwareDetails{NIC: []bmh_v1alpha1.NIC{{Name: "eno1", MAC: "52:54:00:00:00:01"}}},
+			},
+		}
+		data = newExtraManifestsTemplateData(ici, cd, bmh)
+	})
+
+	It("renders the ImageClusterInstall, host and site values", func() {
+		content, err := renderExtraManifestTemplate("t.yaml", []byte(
+			`{{ .ImageClusterInstall.Namespace }}/{{ .ImageClusterInstall.Name }} {{ .ImageClusterInstall.Spec.Hostname }} `+
+				`{{ .ClusterDeployment.ClusterName }}.{{ .ClusterDeployment.BaseDomain }} {{ index .BareMetalHost.Labels "rack" }} `+
+				`{{ range .BareMetalHost.NICs }}{{ .Name }}={{ .MAC }}{{ end }} {{ .Values.vlan }}`), data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("site/ici host.example.com sno.example.com r1 eno1=52:54:00:00:00:01 100"))
+	})
+
+	It("fails on missing values", func() {
+		_, err := renderExtraManifestTemplate("t.yaml", []byte("{{ .Values.missing }}"), data)
+		Expect(err).To(MatchError(ContainSubstring(`map has no entry for key "missing"`)))
+	})
+
+	It("has empty host fields without a BareMetalHost", func() {
+		data = newExtraManifestsTemplateData(&v1alpha1.ImageClusterInstall{}, nil, nil)
+		content, err := renderExtraManifestTemplate("t.yaml", []byte("{{ .BareMetalHost.Name }}{{ len .BareMetalHost.NICs }}"), data)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("0"))
+	})
+})
+
+var _ = Describe("evaluateExtraManifestPolicy", func() {
+	manifests := []extraManifest{
+		{name: "rbac.yaml", source: "ConfigMap manifests", content: []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: admin
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: openshift-config
+`)},
+		{name: "app.yaml", source: "Secret manifests", content: []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: app
+`)},
+	}
+
+	It("has no violations without restrictions", func() {
+		Expect(evaluateExtraManifestPolicy(&v1alpha1.ExtraManifestPolicySpec{}, manifests)).To(BeEmpty())
+	})
+
+	It("reports denied and not allowed kinds", func() {
+		violations := evaluateExtraManifestPolicy(&v1alpha1.ExtraManifestPolicySpec{
+			AllowedKinds: []v1alpha1.ManifestKind{{Kind: "*"}, {Group: "apps", Version: "v1", Kind: "Deployment"}},
+			DeniedKinds:  []v1alpha1.ManifestKind{{Group: "rbac.authorization.k8s.io", Kind: "Cluster*"}},
+		}, manifests)
+		Expect(violations).To(ConsistOf(
+			"extra manifest rbac.yaml in ConfigMap manifests: ClusterRoleBinding.rbac.authorization.k8s.io admin has a kind that is not allowed",
+			"extra manifest rbac.yaml in ConfigMap manifests: ClusterRoleBinding.rbac.authorization.k8s.io admin has a denied kind",
+		))
+	})
+
+	It("reports denied and not allowed namespaces", func() {
+		violations := evaluateExtraManifestPolicy(&v1alpha1.ExtraManifestPolicySpec{
+			AllowedNamespaces: []string{"openshift-*"},
+			DeniedNamespaces:  []string{"openshift-config"},
+		}, manifests)
+		Expect(violations).To(ConsistOf(
+			"extra manifest rbac.yaml in ConfigMap manifests: ConfigMap settings is in denied namespace openshift-config",
+			"extra manifest app.yaml in Secret manifests: Namespace app is in namespace app that is not allowed",
+			"extra manifest app.yaml in Secret manifests: Deployment.apps app is in namespace app that is not allowed",
+		))
+	})
+
+	It("evaluates the items of Lists", func() {
+		listManifests := []extraManifest{{name: "list.yaml", source: "ConfigMap manifests", content: []byte(`apiVersion: v1
+kind: List
+items:
+- apiVersion: rbac.authorization.k8s.io/v1
+  kind: ClusterRoleBinding
+  metadata:
+    name: admin
+- apiVersion: rbac.authorization.k8s.io/v1
+  kind: RoleBindingList
+  items:
+  - apiVersion: rbac.authorization.k8s.io/v1
+    kind: RoleBinding
+    metadata:
+      name: view
+      namespace: openshift-config
+`)}}
+		violations := evaluateExtraManifestPolicy(&v1alpha1.ExtraManifestPolicySpec{
+			DeniedKinds:      []v1alpha1.ManifestKind{{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}},
+			DeniedNamespaces: []string{"openshift-config"},
+		}, listManifests)
+		Expect(violations).To(ConsistOf(
+			"extra manifest list.yaml in ConfigMap manifests: ClusterRoleBinding.rbac.authorization.k8s.io admin has a denied kind",
+			"extra manifest list.yaml in ConfigMap manifests: RoleBinding.rbac.authorization.k8s.io view is in denied namespace openshift-config",
+		))
+	})
+
+	It("reports manifests larger than the size limit", func() {
+		limit := resource.MustParse("150")
+		violations := evaluateExtraManifestPolicy(&v1alpha1.ExtraManifestPolicySpec{MaxManifestSize: &limit}, manifests)
+		Expect(violations).To(ConsistOf(ContainSubstring("extra manifest rbac.yaml in ConfigMap manifests is 182 bytes, larger than the 150 limit")))
+	})
+})
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+// healthWindowEnd returns the end of the post-install health window of an installed cluster, zero when it has none
+func healthWindowEnd(ici *v1alpha1.ImageClusterInstall) time.Time {
+	if ici.Spec.PostInstallHealthWindow == nil {
+		return time.Time{}
+	}
+	completed := findCondition(ici.Status.Conditions, hivev1.ClusterInstallCompleted)
+	if completed == nil {
+		return time.Time{}
+	}
+	return completed.LastTransitionTime.Add(ici.Spec.PostInstallHealthWindow.Duration)
+}
+
+// monitorClusterHealth polls the installed cluster and its readiness gates during its post-install health window. The
+// health is reported by the ClusterHealthy condition, the install conditions are left as they are.
+func (r *ImageClusterInstallMonitor) monitorClusterHealth(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall) (ctrl.Result, error) {
+
+	windowEnd := healthWindowEnd(ici)
+	if windowEnd.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	now := time.Now()
+	if !now.Before(windowEnd) {
+		patch := client.MergeFrom(ici.DeepCopy())
+		if !setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
+			Type:    v1alpha1.ClusterHealthyCondition,
+			Status:  corev1.ConditionUnknown,
+			Reason:  v1alpha1.HealthWatchEndedReason,
+			Message: "The post-install health window ended",
+		}) {
+			return ctrl.Result{}, nil
+		}
+		log.Info("Post-install health window ended")
+		return ctrl.Result{}, r.Status().Patch(ctx, ici, patch)
+	}
+
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	interval := config.installProgressInterval()
+	// The status patch of a check triggers a reconcile, wait for the next check
+	if cond := findCondition(ici.Status.Conditions, v1alpha1.ClusterHealthyCondition); cond != nil {
+		if next := cond.LastProbeTime.Add(interval); now.Before(next) {
+			return ctrl.Result{RequeueAfter: min(next.Sub(now), windowEnd.Sub(now))}, nil
+		}
+	}
+
+	spokeClient, err := r.spokeClient(ctx, ici)
+	if err != nil {
+		log.WithError(err).Error("failed to create spoke client")
+		return ctrl.Result{}, err
+	}
+	status, err := r.spokeInstallStatus(ctx, log, ici, spokeClient)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	switch {
+	case status.Installed:
+		err = r.setClusterHealthCondition(ctx, ici, corev1.ConditionTrue, v1alpha1.ClusterHealthyReason, status.String())
+	case !status.APIReachable:
+		log.Infof("cluster is unreachable: %s", status.String())
+		err = r.setClusterHealthCondition(ctx, ici, corev1.ConditionUnknown, v1alpha1.ClusterUnreachableReason, status.String())
+	default:
+		log.Infof("cluster is unhealthy: %s", status.String())
+		err = r.setClusterHealthCondition(ctx, ici, corev1.ConditionFalse, v1alpha1.ClusterUnhealthyReason, status.String())
+	}
+	if err != nil {
+		log.WithError(err).Error("failed to set cluster health condition")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: min(interval, windowEnd.Sub(now))}, nil
+}
+
+// setClusterHealthCondition records a health check in the ClusterHealthy condition. Its probe time is the time of
+// the check, so it is updated even when the condition doesn't change.
+func (r *ImageClusterInstallMonitor) setClusterHealthCondition(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	status corev1.ConditionStatus,
+	reason, message string) error {
+
+	patch := client.MergeFrom(ici.DeepCopy())
+	setClusterInstallCondition(&ici.Status.Conditions, hivev1.ClusterInstallCondition{
+		Type:    v1alpha1.ClusterHealthyCondition,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	now := metav1.Now()
+	findCondition(ici.Status.Conditions, v1alpha1.ClusterHealthyCondition).LastProbeTime = now
+	if status == corev1.ConditionTrue {
+		ici.Status.LastHealthyTime = &now
+	}
+	return r.Status().Patch(ctx, ici, patch)
+}
@@ -23,6 +23,7 @@ import (
 	// See: https://github.com/opencontainers/go-digest/blob/v1.0.0/README.md#usage
 	_ "crypto/sha256"
 	_ "crypto/sha512"
+	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -35,20 +36,22 @@ import (
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	k8serrors "k8s.io/apimachinery/pkg/util/errors"
 	utilrand "k8s.io/apimachinery/pkg/util/rand"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/containers/image/v5/docker/reference"
 	"github.com/google/uuid"
@@ -60,17 +63,28 @@ import (
 	"github.com/openshift/image-based-install-operator/internal/credentials"
 	"github.com/openshift/image-based-install-operator/internal/filelock"
 	"github.com/openshift/image-based-install-operator/internal/installer"
+	"github.com/openshift/image-based-install-operator/internal/isobuilder"
 	"github.com/openshift/image-based-install-operator/internal/monitor"
 )
 
+// errImageLockContention is returned by an image build that could not acquire the config directory lock
+var errImageLockContention = errors.New("could not acquire lock for image data")
+
 type ImageClusterInstallReconcilerOptions struct {
-	ServiceName             string        `envconfig:"SERVICE_NAME"`
-	ServiceNamespace        string        `envconfig:"SERVICE_NAMESPACE"`
-	ServicePort             string        `envconfig:"SERVICE_PORT"`
-	ServiceScheme           string        `envconfig:"SERVICE_SCHEME"`
-	DataDir                 string        `envconfig:"DATA_DIR" default:"/data"`
-	MaxConcurrentReconciles int           `envconfig:"MAX_CONCURRENT_RECONCILES" default:"1"`
-	DataImageCoolDownPeriod time.Duration `envconfig:"DATA_IMAGE_COOLDOWN_PERIOD" default:"1s"`
+	ServiceName               string        `envconfig:"SERVICE_NAME"`
+	ServiceNamespace          string        `envconfig:"SERVICE_NAMESPACE"`
+	ServicePort               string        `envconfig:"SERVICE_PORT"`
+	ServiceScheme             string        `envconfig:"SERVICE_SCHEME"`
+	DataDir                   string        `envconfig:"DATA_DIR" default:"/data"`
+	MaxConcurrentReconciles   int           `envconfig:"MAX_CONCURRENT_RECONCILES" default:"1"`
+	DataImageCoolDownPeriod   time.Duration `envconfig:"DATA_IMAGE_COOLDOWN_PERIOD" default:"1s"`
+	PodName                   string        `envconfig:"POD_NAME"`
+	LockTimeout               time.Duration `envconfig:"LOCK_TIMEOUT" default:"10s"`
+	LockStaleThreshold        time.Duration `envconfig:"LOCK_STALE_THRESHOLD" default:"15m"`
+	ImageBuildConcurrency     int           `envconfig:"IMAGE_BUILD_CONCURRENCY" default:"1"`
+	ImageBuildQueueSize       int           `envconfig:"IMAGE_BUILD_QUEUE_SIZE" default:"100"`
+	StrictAdmissionValidation bool          `envconfig:"STRICT_ADMISSION_VALIDATION" default:"false"`
+	DefaultsConfigMap         string        `envconfig:"DEFAULTS_CONFIGMAP"`
 }
 
 // ImageClusterInstallReconciler reconciles a ImageClusterInstall object
@@ -83,6 +97,8 @@ type ImageClusterInstallReconciler struct {
 	BaseURL         string
 	NoncachedClient client.Reader
 	Installer       installer.Installer
+	// ImageBuilder runs image builds outside of the reconcile loop, images are built inline when it is nil
+	ImageBuilder *isobuilder.Pool
 }
 
 type imagePullSecret struct {
@@ -98,10 +114,10 @@ const (
 	extraManifestsDir            = "extra-manifests"
 	nmstateSecretKey             = "nmstate"
 	clusterInstallFinalizerName  = "imageclusterinstall." + v1alpha1.Group + "/deprovision"
-	caBundleFileName             = "tls-ca-bundle.pem"
+	caBundleFileName             = v1alpha1.CABundleKey
 	imageBasedInstallInvoker     = "image-based-install"
 	invokerCMFileName            = "invoker-cm.yaml"
-	installTimeoutAnnotation     = "imageclusterinstall." + v1alpha1.Group + "/install-timeout"
+	installTimeoutAnnotation     = v1alpha1.InstallTimeoutAnnotation
 	backupLabel                  = "cluster.open-cluster-management.io/backup"
 	backupLabelValue             = "true"
 	imageBasedConfigFilename     = "image-based-config.yaml"
@@ -110,6 +126,7 @@ const (
 	kubeAdminFile                = "kubeadmin-password"
 	FilesDir                     = "files"
 	IsoName                      = "imagebasedconfig.iso"
+	imageInputsFile              = "inputs.sha256"
 	restoreStatusAnnotation      = "velero.io/restore-status"
 	restoreStatusAnnotationValue = "true"
 	restoreSourceLabel           = "velero.io/restore-name"
@@ -118,10 +135,11 @@ const (
 )
 
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
-//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imageclusterinstalls,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imageclusterinstalls/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imageclusterinstalls/finalizers,verbs=update
+//+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=extramanifestpolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts/finalizers,verbs=update
 //+kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments,verbs=get;list;watch;update;patch
@@ -129,6 +147,8 @@ const (
 //+kubebuilder:rbac:groups=hive.openshift.io,resources=clusterimagesets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=metal3.io,resources=dataimages,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=config.openshift.io,resources=apiservers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=config.openshift.io,resources=imagedigestmirrorsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imagebasedinstalloperatorconfigs,verbs=get;list;watch
 
 func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithFields(logrus.Fields{"name": req.Name, "namespace": req.Namespace})
@@ -165,7 +185,14 @@ func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.
 			log.WithError(err).Error("failed to perform post-cleanup for completed ImageClusterInstall")
 			return ctrl.Result{}, err
 		}
-		return res, nil
+		if !res.IsZero() {
+			return res, nil
+		}
+		res, err = r.removeExpiredImage(ctx, log, ici)
+		if err != nil {
+			log.WithError(err).Error("failed to remove the configuration image of the completed ImageClusterInstall")
+		}
+		return res, err
 	}
 
 	// Nothing to do if the installation process started and the config.iso exists
@@ -214,6 +241,11 @@ func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.
 		r.setRequirementsMetCondition(ctx, ici, cond.Status, cond.Reason, cond.Message)
 	}()
 
+	// Preview mode only renders the install inputs to a ConfigMap, the host is not validated nor configured
+	if previewRequested(ici) {
+		return r.preview(ctx, log, ici, cd, &cond)
+	}
+
 	// 1. Config validation phase
 	// Possible reasons for not meeting requirements and exiting reconcile:
 	// - ConfigurationPending (default): it's either the user needs to complete the ImageClusterInstall definition, or some of
@@ -237,6 +269,18 @@ func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.
 		return res, err
 	}
 
+	if err := r.setDefaults(ctx, log, ici, bmh); err != nil { //nolint:govet // shadow: err in if scope
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = fmt.Sprintf("failed to set defaults in ImageClusterInstall: %s", err)
+		log.Error(err)
+		return ctrl.Result{}, err
+	}
+
+	caCerts, warnings, err := r.validateImageInputs(ctx, log, ici, cd, bmh, &cond)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	if err := r.setClusterInstallMetadata(ctx, log, ici, cd); err != nil { //nolint:govet // shadow: err in if scope
 		cond.Message = "failed to set ClusterMetaData in ImageClusterInstall"
 		log.Error(err)
@@ -245,13 +289,23 @@ func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.
 
 	// 3. Image creation phase
 	// Possible reasons for not meeting requirements and exiting reconcile:
-	// - ImageCreationPending: when lock cannot be acquired, reconcile gets requeued for 5s later to try again.
-	// - ImageCreationFailed (default): any other unexpected error stops the reconcile loop with this reason.
+	// - ImageCreationPending: when lock cannot be acquired within LockTimeout, or the image build is queued or running
+	//   in the ImageBuilder pool, reconcile gets requeued for 5s later to try again.
+	// - ImageCreationFailed (default): any other unexpected error stops the reconcile loop with this reason,
+	//   it is retried after the image-creation-retry-interval annotation when set.
 	cond.Reason = v1alpha1.ImageCreationFailedReason
 	imageUrl, res, err := r.createImage(ctx, ici, req, bmh, cd, &cond, log)
+	if err != nil && cond.Reason == v1alpha1.ImageCreationFailedReason {
+		if interval, ok := imageCreationRetryInterval(ici, log); ok {
+			return ctrl.Result{RequeueAfter: interval}, nil
+		}
+	}
 	if !res.IsZero() || err != nil {
 		return res, err
 	}
+	if err := r.setCACertificates(ctx, ici, caCerts); err != nil { //nolint:govet // shadow: err in if scope
+		log.WithError(err).Error("failed to record the CA certificates")
+	}
 
 	// 4. Host configuration phase
 	// Possible reasons for not meeting requirements and exiting reconcile:
@@ -270,10 +324,74 @@ func (r *ImageClusterInstallReconciler) Reconcile(ctx context.Context, req ctrl.
 	cond.Status = corev1.ConditionTrue
 	cond.Reason = v1alpha1.HostConfigurationSucceededReason
 	cond.Message = "configuration image is attached to the referenced host"
+	if expiryWarnings := caCertificateExpiryWarnings(caCerts, time.Now()); len(expiryWarnings) > 0 {
+		log.Warn(strings.Join(expiryWarnings, "; "))
+		warnings = append(warnings, expiryWarnings...)
+	}
+	if len(warnings) > 0 {
+		cond.Message = fmt.Sprintf("%s, %s", cond.Message, strings.Join(warnings, "; "))
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// validateImageInputs validates the defaulted inputs of the configuration image and the objects they reference. It is
+// shared by Reconcile and RenderImage so an image is never rendered from inputs the reconciler would refuse. cond is
+// set to the reason the inputs are invalid, the trusted CA certificates and the warnings about the inputs are returned.
+func (r *ImageClusterInstallReconciler) validateImageInputs(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	cond *hivev1.ClusterInstallCondition) ([]*x509.Certificate, []string, error) {
+
+	if err := r.validateDisconnectedMirrors(ctx, log, ici); err != nil {
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	// the network config is validated after defaulting so the static addresses are checked against the final
+	// machine networks
+	if err := r.validateHostNetworkConfig(ctx, ici, bmh); err != nil {
+		cond.Reason = v1alpha1.HostValidationFailedReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	// the SSH keys and proxy Secrets are validated on every reconcile, they can change after admission
+	if _, err := r.getSecretValues(ctx, ici); err != nil {
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	// extra manifests that are invalid, would be overwritten, or are denied by the ExtraManifestPolicy stop the
+	// reconcile before the image is created
+	if err := r.checkExtraManifests(ctx, log, ici, cd, bmh, cond); err != nil {
+		return nil, nil, err
+	}
+
+	caCerts, err := r.checkCABundle(ctx, log, ici, cond)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warning, err := r.checkPullSecret(ctx, log, ici, cd, cond)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnings := []string{}
+	if warning != "" {
+		warnings = append(warnings, warning)
+	}
+	return caCerts, warnings, nil
+}
+
 func GetClusterConfigDir(namespacesDir, namespace, uid string) string {
 	return filepath.Join(namespacesDir, namespace, uid, FilesDir, ClusterConfigDir)
 }
@@ -334,15 +452,24 @@ func (r *ImageClusterInstallReconciler) validateHost(
 	log logrus.FieldLogger,
 ) (ctrl.Result, error) {
 
-	if res, err := r.validateBMH(ici, bmh, cond); !res.IsZero() || err != nil {
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	res, err := r.validateBMH(ici, bmh, cond)
+	if err != nil {
 		return res, err
 	}
+	if !res.IsZero() {
+		// the host is still being provisioned or inspected
+		return ctrl.Result{RequeueAfter: config.hostValidationInterval()}, nil
+	}
 
 	if !bmh.Spec.ExternallyProvisioned {
 		log.Infof("Setting BareMetalHost (%s/%s) ExternallyProvisioned spec", bmh.Namespace, bmh.Name)
 		patch := client.MergeFrom(bmh.DeepCopy())
 		bmh.Spec.ExternallyProvisioned = true
-		if err := r.Patch(ctx, bmh, patch); err != nil {
+		if err := r.Patch(ctx, bmh, patch); err != nil { //nolint:govet // shadow: err in if scope
 			return ctrl.Result{}, err
 		}
 
@@ -351,6 +478,21 @@ func (r *ImageClusterInstallReconciler) validateHost(
 	return ctrl.Result{}, nil
 }
 
+// imageCreationRetryInterval returns the interval set by the image-creation-retry-interval annotation, failed image
+// creations are retried with the controller exponential backoff when it is unset or invalid
+func imageCreationRetryInterval(ici *v1alpha1.ImageClusterInstall, log logrus.FieldLogger) (time.Duration, bool) {
+	value, present := ici.Annotations[v1alpha1.ImageCreationRetryIntervalAnnotation]
+	if !present {
+		return 0, false
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval <= 0 {
+		log.Warnf("ignoring invalid %s annotation value %q", v1alpha1.ImageCreationRetryIntervalAnnotation, value)
+		return 0, false
+	}
+	return interval, true
+}
+
 func (r *ImageClusterInstallReconciler) createImage(
 	ctx context.Context,
 	ici *v1alpha1.ImageClusterInstall,
@@ -361,7 +503,14 @@ func (r *ImageClusterInstallReconciler) createImage(
 	log logrus.FieldLogger,
 ) (string, ctrl.Result, error) {
 
-	res, err := r.writeInputData(ctx, log, ici, cd, bmh)
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		cond.Message = "failed to get the operator config"
+		log.Error(err)
+		return "", ctrl.Result{}, err
+	}
+
+	res, pendingMessage, err := r.writeInputData(ctx, log, ici, cd, bmh)
 	if !res.IsZero() || err != nil {
 		if err != nil {
 			cond.Reason = v1alpha1.ImageCreationFailedReason
@@ -369,12 +518,13 @@ func (r *ImageClusterInstallReconciler) createImage(
 			log.Error(err)
 		} else {
 			cond.Reason = v1alpha1.ImageCreationPendingReason
-			cond.Message = "could not acquire lock for image data"
+			cond.Message = pendingMessage
+			res.RequeueAfter = config.imageCreationInterval()
 		}
 		return "", res, err
 	}
 
-	imageUrl, err := url.JoinPath(r.BaseURL, "images", req.Namespace, fmt.Sprintf("%s.iso", ici.ObjectMeta.UID))
+	imageUrl, err := url.JoinPath(config.imageServerURL(r.BaseURL), "images", req.Namespace, fmt.Sprintf("%s.iso", ici.ObjectMeta.UID))
 	if err != nil {
 		cond.Message = "failed to create image url"
 		log.WithError(err).Error(cond.Message)
@@ -468,7 +618,7 @@ func (r *ImageClusterInstallReconciler) validateBMH(
 		cond.Message = fmt.Sprintf("BareMetalHost (%s/%s) provisioning state is: %s, waiting for %s", bmh.Namespace, bmh.Name, bmh.Status.Provisioning.State, bmh_v1alpha1.StateAvailable)
 		cond.Reason = v1alpha1.HostValidationPendingReason
 		r.Log.Info(cond.Message)
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: defaultHostValidationRequeueInterval}, nil
 	}
 
 	// requeue in case of BMH inspection is not ready yet
@@ -476,7 +626,7 @@ func (r *ImageClusterInstallReconciler) validateBMH(
 		cond.Message = fmt.Sprintf("hardware details not found for BareMetalHost %s/%s", bmh.Namespace, bmh.Name)
 		cond.Reason = v1alpha1.HostValidationPendingReason
 		r.Log.Info(cond.Message)
-		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		return ctrl.Result{RequeueAfter: defaultHostValidationRequeueInterval}, nil
 	}
 
 	// do not requeue in case of invalid BMH
@@ -620,8 +770,8 @@ func (r *ImageClusterInstallReconciler) mapBMHToICI(ctx context.Context, obj cli
 	}
 	listOptions := []client.ListOption{
 		client.MatchingFields{
-			".spec.bareMetalHostRef.name":      bmhName,
-			".spec.bareMetalHostRef.namespace": bmhNamespace},
+			v1alpha1.BareMetalHostRefNameIndex:      bmhName,
+			v1alpha1.BareMetalHostRefNamespaceIndex: bmhNamespace},
 	}
 	iciList := &v1alpha1.ImageClusterInstallList{}
 	if err := r.List(ctx, iciList, listOptions...); err != nil {
@@ -681,6 +831,27 @@ func (r *ImageClusterInstallReconciler) SetupWithManager(mgr ctrl.Manager) error
 	if err := r.addIndexforBaremetalHostRef(mgr); err != nil {
 		return err
 	}
+	if err := r.addIndexForReferencedInputs(mgr); err != nil {
+		return err
+	}
+
+	// the manager only caches the Secrets the operator creates, the referenced Secrets are watched through a cache of
+	// the metadata of the Secrets labelled for backup, a label the reconcile sets on every referenced Secret
+	secretsCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		HTTPClient:           mgr.GetHTTPClient(),
+		Scheme:               mgr.GetScheme(),
+		Mapper:               mgr.GetRESTMapper(),
+		DefaultLabelSelector: labels.SelectorFromSet(labels.Set{backupLabel: backupLabelValue}),
+	})
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(secretsCache); err != nil {
+		return err
+	}
+	secretMetadata := &metav1.PartialObjectMetadata{}
+	secretMetadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+
 	r.Log.Infof("Setting up controller ImageClusterInstallReconciler with %d concurrent reconciles", r.Options.MaxConcurrentReconciles)
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("ImageClusterInstallReconciler").
@@ -688,11 +859,15 @@ func (r *ImageClusterInstallReconciler) SetupWithManager(mgr ctrl.Manager) error
 		For(&v1alpha1.ImageClusterInstall{}).
 		Watches(&bmh_v1alpha1.BareMetalHost{}, handler.EnqueueRequestsFromMapFunc(r.mapBMHToICI)).
 		Watches(&hivev1.ClusterDeployment{}, handler.EnqueueRequestsFromMapFunc(r.mapCDToICI)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapConfigMapToICIs)).
+		WatchesRawSource(source.Kind[client.Object](secretsCache, secretMetadata, handler.EnqueueRequestsFromMapFunc(r.mapSecretToICIs))).
+		Watches(&v1alpha1.ExtraManifestPolicy{}, handler.EnqueueRequestsFromMapFunc(r.mapExtraManifestPolicyToICIs)).
+		Watches(&v1alpha1.ImageBasedInstallOperatorConfig{}, handler.EnqueueRequestsFromMapFunc(r.mapOperatorConfigToICIs)).
 		Complete(r)
 }
 
 func (r *ImageClusterInstallReconciler) addIndexforBaremetalHostRef(mgr ctrl.Manager) error {
-	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, ".spec.bareMetalHostRef.name", func(rawObj client.Object) []string {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, v1alpha1.BareMetalHostRefNameIndex, func(rawObj client.Object) []string {
 		ici, ok := rawObj.(*v1alpha1.ImageClusterInstall)
 		if !ok || ici.Spec.BareMetalHostRef == nil {
 			return nil
@@ -701,7 +876,7 @@ func (r *ImageClusterInstallReconciler) addIndexforBaremetalHostRef(mgr ctrl.Man
 	}); err != nil {
 		return err
 	}
-	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, ".spec.bareMetalHostRef.namespace", func(rawObj client.Object) []string {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, v1alpha1.BareMetalHostRefNamespaceIndex, func(rawObj client.Object) []string {
 		ici, ok := rawObj.(*v1alpha1.ImageClusterInstall)
 		if !ok || ici.Spec.BareMetalHostRef == nil {
 			return nil
@@ -859,9 +1034,13 @@ func (r *ImageClusterInstallReconciler) labelBMHForBackup(ctx context.Context, b
 }
 
 func (r *ImageClusterInstallReconciler) labelReferencedObjectsForBackup(ctx context.Context, log logrus.FieldLogger, ici *v1alpha1.ImageClusterInstall, cd *hivev1.ClusterDeployment) {
-	if ici.Spec.CABundleRef != nil {
-		caBundleKey := types.NamespacedName{Name: ici.Spec.CABundleRef.Name, Namespace: ici.Namespace}
-		if err := r.labelConfigMapForBackup(ctx, caBundleKey); err != nil {
+	for _, source := range caBundleSources(ici) {
+		caBundleKey := types.NamespacedName{Name: source.Name, Namespace: ici.Namespace}
+		if v1alpha1.CABundleSourceKind(source) == v1alpha1.CABundleSourceKindSecret {
+			if err := r.labelSecretForBackup(ctx, caBundleKey); err != nil {
+				log.WithError(err).Errorf("failed to label Secret %s for backup", caBundleKey)
+			}
+		} else if err := r.labelConfigMapForBackup(ctx, caBundleKey); err != nil {
 			log.WithError(err).Errorf("failed to label ConfigMap %s for backup", caBundleKey)
 		}
 	}
@@ -873,6 +1052,20 @@ func (r *ImageClusterInstallReconciler) labelReferencedObjectsForBackup(ctx cont
 		}
 	}
 
+	for _, manifestRef := range ici.Spec.PostInstallManifestsRefs {
+		manifestKey := types.NamespacedName{Name: manifestRef.Name, Namespace: ici.Namespace}
+		if err := r.labelConfigMapForBackup(ctx, manifestKey); err != nil {
+			log.WithError(err).Errorf("failed to label ConfigMap %s for backup", manifestKey)
+		}
+	}
+
+	for _, manifestRef := range ici.Spec.ExtraManifestsSecretRefs {
+		manifestKey := types.NamespacedName{Name: manifestRef.Name, Namespace: ici.Namespace}
+		if err := r.labelSecretForBackup(ctx, manifestKey); err != nil {
+			log.WithError(err).Errorf("failed to label Secret %s for backup", manifestKey)
+		}
+	}
+
 	if cd != nil {
 		reconfigKey := types.NamespacedName{Name: credentials.SeedReconfigurationSecretName(cd.Name), Namespace: cd.Namespace}
 		if err := r.labelSecretForBackup(ctx, reconfigKey); err != nil {
@@ -887,6 +1080,27 @@ func (r *ImageClusterInstallReconciler) labelReferencedObjectsForBackup(ctx cont
 		}
 	}
 
+	for _, ref := range []*corev1.LocalObjectReference{ici.Spec.SSHKeysSecretRef, proxySecretRef(ici)} {
+		if ref == nil {
+			continue
+		}
+		secretKey := types.NamespacedName{Name: ref.Name, Namespace: ici.Namespace}
+		if err := r.labelSecretForBackup(ctx, secretKey); err != nil {
+			log.WithError(err).Errorf("failed to label Secret %s for backup", secretKey)
+		}
+	}
+
+	if ref := ici.Spec.NetworkConfigRef; ref != nil {
+		networkConfigKey := types.NamespacedName{Name: ref.Name, Namespace: ici.Namespace}
+		if ref.Kind == v1alpha1.NetworkConfigKindConfigMap {
+			if err := r.labelConfigMapForBackup(ctx, networkConfigKey); err != nil {
+				log.WithError(err).Errorf("failed to label ConfigMap %s for backup", networkConfigKey)
+			}
+		} else if err := r.labelSecretForBackup(ctx, networkConfigKey); err != nil {
+			log.WithError(err).Errorf("failed to label Secret %s for backup", networkConfigKey)
+		}
+	}
+
 	if ici.Spec.BareMetalHostRef != nil {
 		if err := r.labelBMHForBackup(ctx, ici.Spec.BareMetalHostRef); err != nil {
 			log.WithError(err).Errorf("failed to label BMH %s/%s for backup", ici.Spec.BareMetalHostRef.Namespace, ici.Spec.BareMetalHostRef.Name)
@@ -907,6 +1121,13 @@ func (r *ImageClusterInstallReconciler) labelReferencedObjectsForBackup(ctx cont
 	}
 }
 
+func proxySecretRef(ici *v1alpha1.ImageClusterInstall) *corev1.LocalObjectReference {
+	if ici.Spec.Proxy == nil {
+		return nil
+	}
+	return ici.Spec.Proxy.SecretRef
+}
+
 func (r *ImageClusterInstallReconciler) configDirs(ici *v1alpha1.ImageClusterInstall) (string, string, error) {
 	lockDir := filepath.Join(r.Options.DataDir, "namespaces", ici.Namespace, string(ici.ObjectMeta.UID))
 	filesDir := filepath.Join(lockDir, FilesDir)
@@ -917,102 +1138,251 @@ func (r *ImageClusterInstallReconciler) configDirs(ici *v1alpha1.ImageClusterIns
 	return lockDir, filesDir, nil
 }
 
+// lockOptions returns the options used to wait for the file lock of an ImageClusterInstall config directory
+func (r *ImageClusterInstallReconciler) lockOptions(ctx context.Context, log logrus.FieldLogger) filelock.LockOptions {
+	pod := r.Options.PodName
+	if pod == "" {
+		pod, _ = os.Hostname()
+	}
+	return filelock.LockOptions{
+		Owner: filelock.Owner{
+			Pod:         pod,
+			ReconcileID: string(controller.ReconcileIDFromContext(ctx)),
+		},
+		Timeout:        r.Options.LockTimeout,
+		StaleThreshold: r.Options.LockStaleThreshold,
+		Log:            log,
+	}
+}
+
 // writeInputData writes files required by openshift installer to create image-based configuration iso
 // and then runs installer to create it.
+// When an ImageBuilder is configured the image is built by the pool and writeInputData only reports the
+// progress of the build, the returned message describes why the reconcile needs to be requeued.
 func (r *ImageClusterInstallReconciler) writeInputData(
 	ctx context.Context, log logrus.FieldLogger,
 	ici *v1alpha1.ImageClusterInstall,
 	cd *hivev1.ClusterDeployment,
-	bmh *bmh_v1alpha1.BareMetalHost) (ctrl.Result, error) {
+	bmh *bmh_v1alpha1.BareMetalHost) (ctrl.Result, string, error) {
 
 	lockDir, filesDir, err := r.configDirs(ici)
 	if err != nil {
-		return ctrl.Result{}, err
+		return ctrl.Result{}, "", err
 	}
+	isoWorkDir := filepath.Join(filesDir, ClusterConfigDir)
 
-	locked, lockErr, funcErr := filelock.WithWriteLock(lockDir, func() (err error) {
+	inputsHash, err := r.imageInputsHash(ctx, ici, cd, bmh)
+	if err != nil {
+		return ctrl.Result{}, "", err
+	}
 
-		isoWorkDir := filepath.Join(filesDir, ClusterConfigDir)
-		if verifyIsoAndAuthExists(isoWorkDir) {
-			// in case image exists we should ensure credentials in case something failed before it
-			return r.ensureCreds(ctx, log, cd, isoWorkDir)
+	if r.ImageBuilder == nil || imageUpToDate(ici, isoWorkDir, inputsHash) {
+		if r.ImageBuilder != nil {
+			// the image was built already, only the credentials need to be ensured
+			r.ImageBuilder.Forget(string(ici.UID))
 		}
-		log.Info("writing input data for image cluster install")
-
-		os.RemoveAll(isoWorkDir)
-		if err := os.MkdirAll(isoWorkDir, 0700); err != nil {
-			return err
+		err = r.buildImage(ctx, log, r.lockOptions(ctx, log), ici, cd, bmh, lockDir, isoWorkDir, inputsHash)
+		if errors.Is(err, errImageLockContention) {
+			log.Info("requeueing due to lock contention")
+			return ctrl.Result{RequeueAfter: defaultImageCreationRequeueInterval}, err.Error(), nil
 		}
+		return ctrl.Result{}, "", err
+	}
 
-		psData, err := r.getValidPullSecret(ctx, cd.Spec.PullSecretRef, cd.Namespace)
-		if err != nil {
-			return fmt.Errorf("failed to get valid pull secret: %w", err)
-		}
+	return r.queueImageBuild(ctx, log, ici, cd, bmh, lockDir, isoWorkDir, inputsHash)
+}
 
-		caBundle, err := r.getCABundle(ctx, ici.Spec.CABundleRef, ici.Namespace)
+// queueImageBuild submits the image build to the ImageBuilder pool and reports its progress
+func (r *ImageClusterInstallReconciler) queueImageBuild(
+	ctx context.Context, log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	lockDir, isoWorkDir, inputsHash string) (ctrl.Result, string, error) {
+
+	key := string(ici.UID)
+	status, found := r.ImageBuilder.Status(key)
+	if found && status.State == isobuilder.StateSucceeded && !imageUpToDate(ici, isoWorkDir, inputsHash) {
+		// the tracked inputs changed while the build was queued or running, the image is built again from
+		// the current ones so a stale image is never attached to the host
+		log.Info("image inputs changed during the image build, rebuilding the image")
+		r.ImageBuilder.Forget(key)
+		found = false
+	}
+	if !found {
+		// the build runs after this reconcile returns so it must not share objects with it, the lock is
+		// taken on behalf of this reconcile so its holder can be traced back to it
+		ici, cd, bmh := ici.DeepCopy(), cd.DeepCopy(), bmh.DeepCopy()
+		lockOpts := r.lockOptions(ctx, log)
+		err := r.ImageBuilder.Submit(key, func(buildCtx context.Context) error {
+			return r.buildImage(buildCtx, log, lockOpts, ici, cd, bmh, lockDir, isoWorkDir, inputsHash)
+		})
+		if errors.Is(err, isobuilder.ErrQueueFull) {
+			log.Info("requeueing due to full image build queue")
+			return ctrl.Result{RequeueAfter: defaultImageCreationRequeueInterval}, err.Error(), nil
+		}
 		if err != nil {
-			return fmt.Errorf("failed to get ca bundle: %w", err)
+			return ctrl.Result{}, "", err
 		}
-
-		if err := r.generateExtraManifests(isoWorkDir, ici, ctx); err != nil {
-			return fmt.Errorf("failed to generate extra manifests: %w", err)
+		log.Info("queued image build")
+		status, _ = r.ImageBuilder.Status(key)
+	}
+
+	switch status.State {
+	case isobuilder.StateQueued:
+		return ctrl.Result{RequeueAfter: defaultImageCreationRequeueInterval},
+			fmt.Sprintf("image build is queued behind %d other builds", status.Position), nil
+	case isobuilder.StateRunning:
+		return ctrl.Result{RequeueAfter: defaultImageCreationRequeueInterval},
+			fmt.Sprintf("image build is in progress since %s", status.StartedAt.Format(time.RFC3339)), nil
+	case isobuilder.StateFailed:
+		r.ImageBuilder.Forget(key)
+		if errors.Is(status.Err, errImageLockContention) {
+			log.Info("requeueing due to lock contention")
+			return ctrl.Result{RequeueAfter: defaultImageCreationRequeueInterval}, status.Err.Error(), nil
 		}
+		return ctrl.Result{}, "", status.Err
+	default:
+		r.ImageBuilder.Forget(key)
+		return ctrl.Result{}, "", nil
+	}
+}
 
-		configFilePath := isoWorkDir
-		idData, secretsExist, err := r.Credentials.ClusterIdentitySecrets(ctx, cd)
-		if err != nil {
-			return fmt.Errorf("failed to check existence of cluster identity secrets: %w", err)
-		}
-		// if the secrets exist, create the config files in a temp dir to build the initial seed reconfig
-		// if they don't, create them in the working dir
-		if secretsExist {
-			tmpDirPath, err := os.MkdirTemp("", "asset-generation-")
-			if err != nil {
-				return fmt.Errorf("failed to create tempdir: %w", err)
-			}
-			defer os.RemoveAll(tmpDirPath)
-			configFilePath = tmpDirPath
-		}
+// buildImage writes the installer input files to isoWorkDir and creates the configuration iso while holding
+// the config directory lock taken with lockOpts. inputsHash is recorded with the image so it is rebuilt when the
+// tracked inputs change.
+func (r *ImageClusterInstallReconciler) buildImage(
+	ctx context.Context, log logrus.FieldLogger,
+	lockOpts filelock.LockOptions,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	lockDir, isoWorkDir, inputsHash string) error {
 
-		log.Info("writing install config")
-		if err := installer.WriteInstallConfig(ici, cd, psData, caBundle, filepath.Join(configFilePath, installConfigFilename)); err != nil {
-			return fmt.Errorf("failed to write install config: %w", err)
+	locked, lockErr, funcErr := filelock.WithWriteLockContext(ctx, lockDir, lockOpts, func() error {
+		if imageUpToDate(ici, isoWorkDir, inputsHash) {
+			// in case image exists we should ensure credentials in case something failed before it
+			return r.ensureCreds(ctx, log, cd, isoWorkDir)
 		}
-
-		if err := r.writeImageBaseConfig(ctx, ici, bmh, filepath.Join(configFilePath, imageBasedConfigFilename)); err != nil {
-			return fmt.Errorf("failed to write install config: %w", err)
+		if verifyIsoAndAuthExists(isoWorkDir) {
+			log.Info("image inputs changed, rebuilding the image")
 		}
 
-		if secretsExist {
-			if err := r.Installer.WriteReinstallData(ctx, configFilePath, isoWorkDir, idData); err != nil {
-				return fmt.Errorf("failed to write reinstall data: %w", err)
-			}
+		if err := r.writeImage(ctx, log, ici, cd, bmh, isoWorkDir); err != nil {
+			return err
 		}
-
-		if err := r.Installer.CreateInstallationIso(ctx, log, isoWorkDir); err != nil {
-			return fmt.Errorf("failed to create installation iso: %w", err)
+		if err := os.WriteFile(filepath.Join(isoWorkDir, imageInputsFile), []byte(inputsHash), 0600); err != nil {
+			return fmt.Errorf("failed to write image inputs hash: %w", err)
 		}
 
 		return r.ensureCreds(ctx, log, cd, isoWorkDir)
-
 	})
 	if lockErr != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to acquire file lock: %w", lockErr)
+		return fmt.Errorf("failed to acquire file lock: %w", lockErr)
 	}
 	if funcErr != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to write input data: %w", funcErr)
+		return fmt.Errorf("failed to write input data: %w", funcErr)
 	}
 	if !locked {
-		log.Info("requeueing due to lock contention")
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+		return errImageLockContention
 	}
 
-	return ctrl.Result{}, nil
+	return nil
+}
+
+// writeImage writes the installer input files to isoWorkDir and runs the installer to create the configuration iso
+// and the auth files in it
+func (r *ImageClusterInstallReconciler) writeImage(
+	ctx context.Context, log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	isoWorkDir string) error {
+
+	log.Info("writing input data for image cluster install")
+
+	os.RemoveAll(isoWorkDir)
+	if err := os.MkdirAll(isoWorkDir, 0700); err != nil {
+		return err
+	}
+
+	configFilePath := isoWorkDir
+	idData, secretsExist, err := r.Credentials.ClusterIdentitySecrets(ctx, cd)
+	if err != nil {
+		return fmt.Errorf("failed to check existence of cluster identity secrets: %w", err)
+	}
+	// if the secrets exist, create the config files in a temp dir to build the initial seed reconfig
+	// if they don't, create them in the working dir
+	if secretsExist {
+		tmpDirPath, err := os.MkdirTemp("", "asset-generation-")
+		if err != nil {
+			return fmt.Errorf("failed to create tempdir: %w", err)
+		}
+		defer os.RemoveAll(tmpDirPath)
+		configFilePath = tmpDirPath
+	}
+
+	if err := r.writeInstallerInputs(ctx, log, ici, cd, bmh, isoWorkDir, configFilePath); err != nil {
+		return err
+	}
+
+	if secretsExist {
+		if err := r.Installer.WriteReinstallData(ctx, configFilePath, isoWorkDir, idData); err != nil {
+			return fmt.Errorf("failed to write reinstall data: %w", err)
+		}
+	}
+
+	if err := r.Installer.CreateInstallationIso(ctx, log, isoWorkDir); err != nil {
+		return fmt.Errorf("failed to create installation iso: %w", err)
+	}
+
+	return nil
+}
+
+// writeInstallerInputs writes the extra manifests to manifestsDir and the install-config and image-based-config to configDir
+func (r *ImageClusterInstallReconciler) writeInstallerInputs(
+	ctx context.Context, log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
+	manifestsDir, configDir string) error {
+
+	psData, err := r.pullSecret(ctx, cd)
+	if err != nil {
+		return fmt.Errorf("failed to get valid pull secret: %w", err)
+	}
+
+	caBundle, err := r.getCABundle(ctx, ici)
+	if err != nil {
+		return fmt.Errorf("failed to get ca bundle: %w", err)
+	}
+
+	if err := r.generateExtraManifests(manifestsDir, ici, cd, bmh, ctx); err != nil {
+		return fmt.Errorf("failed to generate extra manifests: %w", err)
+	}
+
+	secretValues, err := r.getSecretValues(ctx, ici)
+	if err != nil {
+		return err
+	}
+
+	log.Info("writing install config")
+	if err := installer.WriteInstallConfig(ici, cd, psData, caBundle, secretValues, filepath.Join(configDir, installConfigFilename)); err != nil {
+		return fmt.Errorf("failed to write install config: %w", err)
+	}
+
+	if err := r.writeImageBaseConfig(ctx, ici, bmh, filepath.Join(configDir, imageBasedConfigFilename)); err != nil {
+		return fmt.Errorf("failed to write image based config: %w", err)
+	}
+
+	return nil
 }
 
 func (r *ImageClusterInstallReconciler) generateExtraManifests(
 	clusterConfigPath string,
 	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost,
 	ctx context.Context) error {
 
 	extraManifestsPath := filepath.Join(clusterConfigPath, extraManifestsDir)
@@ -1027,26 +1397,18 @@ func (r *ImageClusterInstallReconciler) generateExtraManifests(
 		return fmt.Errorf("failed to write %s config map: %w", monitor.IBIOStartTimeCM, err)
 	}
 
-	if ici.Spec.ExtraManifestsRefs != nil {
-		for _, cmRef := range ici.Spec.ExtraManifestsRefs {
-			cm := &corev1.ConfigMap{}
-			key := types.NamespacedName{Name: cmRef.Name, Namespace: ici.Namespace}
-			if err := r.Get(ctx, key, cm); err != nil {
-				return fmt.Errorf("failed to get extraManifests config map %w", err)
-			}
-
-			for name, content := range cm.Data {
-				var y interface{}
-				if err := yaml.Unmarshal([]byte(content), &y); err != nil {
-					return fmt.Errorf("failed to validate manifest file %s: %w", name, err)
-				}
-				if err := os.WriteFile(filepath.Join(extraManifestsPath, name), []byte(content), 0644); err != nil {
-					return fmt.Errorf("failed to write extra manifest file: %w", err)
-				}
-			}
-		}
+	manifests, err := r.extraManifests(ctx, ici, cd, bmh)
+	if err != nil {
+		return err
 	}
-	return nil
+	violations, enforced, err := r.extraManifestPolicyViolations(ctx, manifests)
+	if err != nil {
+		return err
+	}
+	if enforced && len(violations) > 0 {
+		return extraManifestPolicyError(violations)
+	}
+	return writeExtraManifests(extraManifestsPath, manifests)
 }
 
 func (r *ImageClusterInstallReconciler) ensureCreds(ctx context.Context, log logrus.FieldLogger, cd *hivev1.ClusterDeployment, workDir string) error {
@@ -1066,6 +1428,15 @@ func (r *ImageClusterInstallReconciler) ensureCreds(ctx context.Context, log log
 }
 
 func (r *ImageClusterInstallReconciler) imageSetRegistry(ctx context.Context, ici *v1alpha1.ImageClusterInstall) (string, error) {
+	repository, err := r.releaseImageRepository(ctx, ici)
+	if err != nil {
+		return "", err
+	}
+	return strings.Split(repository, "/")[0], nil
+}
+
+// releaseImageRepository returns the repository of the release image of the ClusterImageSet
+func (r *ImageClusterInstallReconciler) releaseImageRepository(ctx context.Context, ici *v1alpha1.ImageClusterInstall) (string, error) {
 	cis := hivev1.ClusterImageSet{}
 	key := types.NamespacedName{Name: ici.Spec.ImageSetRef.Name, Namespace: ici.Namespace}
 	if err := r.Get(ctx, key, &cis); err != nil {
@@ -1083,41 +1454,89 @@ func (r *ImageClusterInstallReconciler) imageSetRegistry(ctx context.Context, ic
 		return "", fmt.Errorf("failed to parse registry name from image %s", ref)
 	}
 
-	return strings.Split(namedRef.Name(), "/")[0], nil
+	return namedRef.Name(), nil
 }
 
-// nmstateConfig in case bmh was configured with static networking we want to use this configuration
+// nmstateConfig returns the static network configuration of the host, from the networkConfigRef of the
+// ImageClusterInstall or else from the preprovisioning network data of the BareMetalHost
 func (r *ImageClusterInstallReconciler) nmstateConfig(
 	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
 	bmh *bmh_v1alpha1.BareMetalHost) (string, error) {
-	if bmh == nil || bmh.Spec.PreprovisioningNetworkDataName == "" {
+
+	var nmstate []byte
+	var err error
+	switch {
+	case ici.Spec.NetworkConfigRef != nil:
+		nmstate, err = r.referencedNetworkConfig(ctx, ici.Namespace, ici.Spec.NetworkConfigRef)
+	case bmh != nil && bmh.Spec.PreprovisioningNetworkDataName != "":
+		key := types.NamespacedName{Name: bmh.Spec.PreprovisioningNetworkDataName, Namespace: bmh.Namespace}
+		nmstate, err = r.networkConfigSecret(ctx, key)
+	default:
 		return "", nil
 	}
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := parseNMState(nmstate); err != nil {
+		return "", err
+	}
 
+	return string(nmstate), nil
+}
+
+func (r *ImageClusterInstallReconciler) referencedNetworkConfig(
+	ctx context.Context,
+	namespace string,
+	ref *v1alpha1.NetworkConfigReference) ([]byte, error) {
+
+	key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+	if ref.Kind != v1alpha1.NetworkConfigKindConfigMap {
+		return r.networkConfigSecret(ctx, key)
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, key, cm); err != nil {
+		return nil, fmt.Errorf("failed to get network config ConfigMap %s: %w", key, err)
+	}
+	nmstate, present := cm.Data[nmstateSecretKey]
+	if !present {
+		return nil, fmt.Errorf("referenced networking ConfigMap %s does not contain the required key %s", key, nmstateSecretKey)
+	}
+	return []byte(nmstate), nil
+}
+
+func (r *ImageClusterInstallReconciler) networkConfigSecret(ctx context.Context, key types.NamespacedName) ([]byte, error) {
 	nmstateConfigSecret := &corev1.Secret{}
-	key := types.NamespacedName{Name: bmh.Spec.PreprovisioningNetworkDataName, Namespace: bmh.Namespace}
 	if err := r.NoncachedClient.Get(ctx, key, nmstateConfigSecret); err != nil {
-		return "", fmt.Errorf("failed to get network config secret %s: %w", key, err)
+		return nil, fmt.Errorf("failed to get network config secret %s: %w", key, err)
 	}
 
 	nmstate, present := nmstateConfigSecret.Data[nmstateSecretKey]
 	if !present {
-		return "", fmt.Errorf("referenced networking secret %s does not contain the required key %s", key, nmstateSecretKey)
+		return nil, fmt.Errorf("referenced networking secret %s does not contain the required key %s", key, nmstateSecretKey)
 	}
+	return nmstate, nil
+}
 
-	var nmstateData map[string]any
-	if err := yaml.Unmarshal(nmstate, &nmstateData); err != nil {
-		return "", fmt.Errorf("failed to unmarshal nmstate data: %w", err)
+// networkConfig returns the parsed nmstate configuration of the host, nil when it has none
+func (r *ImageClusterInstallReconciler) networkConfig(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	bmh *bmh_v1alpha1.BareMetalHost) (*nmstateNetworkConfig, error) {
+	nmstate, err := r.nmstateConfig(ctx, ici, bmh)
+	if err != nil || nmstate == "" {
+		return nil, err
 	}
-
-	return string(nmstate), nil
+	return parseNMState([]byte(nmstate))
 }
 
 func (r *ImageClusterInstallReconciler) writeImageBaseConfig(ctx context.Context,
 	ici *v1alpha1.ImageClusterInstall,
 	bmh *bmh_v1alpha1.BareMetalHost,
 	file string) error {
-	nmstate, err := r.nmstateConfig(ctx, bmh)
+	nmstate, err := r.nmstateConfig(ctx, ici, bmh)
 	if err != nil {
 		return err
 	}
@@ -1128,30 +1547,27 @@ func (r *ImageClusterInstallReconciler) writeImageBaseConfig(ctx context.Context
 	return installer.WriteImageBaseConfig(ctx, ici, releaseRegistry, nmstate, file)
 }
 
-func (r *ImageClusterInstallReconciler) getCABundle(ctx context.Context, ref *corev1.LocalObjectReference, ns string) (string, error) {
-	if ref == nil {
-		return "", nil
-	}
-
-	cm := &corev1.ConfigMap{}
-	key := types.NamespacedName{Name: ref.Name, Namespace: ns}
-	if err := r.Get(ctx, key, cm); err != nil {
-		return "", fmt.Errorf("failed to get CABundle config map: %w", err)
-	}
+func (r *ImageClusterInstallReconciler) setClusterInstallMetadata(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment) error {
 
-	data, ok := cm.Data[caBundleFileName]
-	if !ok {
-		return "", fmt.Errorf("%s key missing from CABundle config map", caBundleFileName)
+	metadata, err := r.clusterInstallMetadata(ctx, log, ici, cd)
+	if err != nil || metadata == nil {
+		return err
 	}
-
-	return data, nil
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Spec.ClusterMetadata = metadata
+	return r.Patch(ctx, ici, patch)
 }
 
-func (r *ImageClusterInstallReconciler) setClusterInstallMetadata(
+// clusterInstallMetadata returns the cluster metadata of ici, or nil when the one in its spec is already complete
+func (r *ImageClusterInstallReconciler) clusterInstallMetadata(
 	ctx context.Context,
 	log logrus.FieldLogger,
 	ici *v1alpha1.ImageClusterInstall,
-	cd *hivev1.ClusterDeployment) error {
+	cd *hivev1.ClusterDeployment) (*hivev1.ClusterMetadata, error) {
 
 	// current state of cluster metadata is the source of truth for IDs if they are set
 	kubeconfigSecret := credentials.KubeconfigSecretName(cd.Name)
@@ -1161,13 +1577,13 @@ func (r *ImageClusterInstallReconciler) setClusterInstallMetadata(
 		ici.Spec.ClusterMetadata.InfraID != "" &&
 		ici.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name == kubeconfigSecret &&
 		ici.Spec.ClusterMetadata.AdminPasswordSecretRef.Name == kubeadminPasswordSecret {
-		return nil
+		return nil, nil
 	}
 
 	// do this here rather than in the secret import because these values are needed as input to the image based config file
 	secretClusterID, secretInfraID, err := r.Credentials.SeedReconfigSecretClusterIDs(ctx, log, cd)
 	if err != nil {
-		return fmt.Errorf("failed to get seed reconfiguration secret: %w", err)
+		return nil, fmt.Errorf("failed to get seed reconfiguration secret: %w", err)
 	}
 
 	var clusterID string
@@ -1192,8 +1608,7 @@ func (r *ImageClusterInstallReconciler) setClusterInstallMetadata(
 		log.Infof("created new infra ID %s", infraID)
 	}
 
-	patch := client.MergeFrom(ici.DeepCopy())
-	ici.Spec.ClusterMetadata = &hivev1.ClusterMetadata{
+	return &hivev1.ClusterMetadata{
 		ClusterID: clusterID,
 		InfraID:   infraID,
 		AdminKubeconfigSecretRef: corev1.LocalObjectReference{
@@ -1202,9 +1617,7 @@ func (r *ImageClusterInstallReconciler) setClusterInstallMetadata(
 		AdminPasswordSecretRef: &corev1.LocalObjectReference{
 			Name: kubeadminPasswordSecret,
 		},
-	}
-
-	return r.Patch(ctx, ici, patch)
+	}, nil
 }
 
 // Implementation from openshift-installer here: https://github.com/openshift/installer/blob/67c114a4b82ed509dc292fa81d63030c8b4118ee/pkg/asset/installconfig/clusterid.go#L60-L79
@@ -1250,6 +1663,57 @@ func (r *ImageClusterInstallReconciler) getValidPullSecret(ctx context.Context,
 	return string(psData), nil
 }
 
+// getSecretValues reads the proxy URLs and the SSH keys from the Secrets referenced by the ImageClusterInstall
+func (r *ImageClusterInstallReconciler) getSecretValues(ctx context.Context, ici *v1alpha1.ImageClusterInstall) (installer.SecretValues, error) {
+	values := installer.SecretValues{}
+	if ici.Spec.Proxy != nil && ici.Spec.Proxy.SecretRef != nil {
+		key := types.NamespacedName{Name: ici.Spec.Proxy.SecretRef.Name, Namespace: ici.Namespace}
+		s := &corev1.Secret{}
+		if err := r.NoncachedClient.Get(ctx, key, s); err != nil {
+			return values, fmt.Errorf("failed to get proxy secret %s: %w", key, err)
+		}
+		values.HTTPProxy = string(s.Data[v1alpha1.HTTPProxySecretKey])
+		values.HTTPSProxy = string(s.Data[v1alpha1.HTTPSProxySecretKey])
+		if values.HTTPProxy == "" && values.HTTPSProxy == "" {
+			return values, fmt.Errorf("proxy secret %s has neither a %s nor a %s key", key, v1alpha1.HTTPProxySecretKey, v1alpha1.HTTPSProxySecretKey)
+		}
+		// the Secret may have changed since the webhook admitted the ImageClusterInstall
+		if values.HTTPProxy != "" {
+			if err := validateHTTPURL(values.HTTPProxy); err != nil {
+				return values, fmt.Errorf("invalid %s in proxy secret %s: %w", v1alpha1.HTTPProxySecretKey, key, err)
+			}
+		}
+		if values.HTTPSProxy != "" {
+			if err := validateHTTPURL(values.HTTPSProxy); err != nil {
+				return values, fmt.Errorf("invalid %s in proxy secret %s: %w", v1alpha1.HTTPSProxySecretKey, key, err)
+			}
+		}
+	}
+
+	if ici.Spec.SSHKeysSecretRef != nil {
+		key := types.NamespacedName{Name: ici.Spec.SSHKeysSecretRef.Name, Namespace: ici.Namespace}
+		s := &corev1.Secret{}
+		if err := r.NoncachedClient.Get(ctx, key, s); err != nil {
+			return values, fmt.Errorf("failed to get SSH keys secret %s: %w", key, err)
+		}
+		keys, ok := s.Data[v1alpha1.SSHKeysSecretKey]
+		if !ok {
+			return values, fmt.Errorf("SSH keys secret %s did not contain key %s", key, v1alpha1.SSHKeysSecretKey)
+		}
+		if err := v1alpha1.ValidateSSHAuthorizedKeys(string(keys)); err != nil {
+			return values, fmt.Errorf("invalid SSH key in SSH keys secret %s: %w", key, err)
+		}
+		for _, line := range strings.Split(string(keys), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				values.SSHKeys = append(values.SSHKeys, line)
+			}
+		}
+	}
+
+	return values, nil
+}
+
 // validatePullSecret checks if the given string is a valid image pull secret and returns an error if not.
 func (r *ImageClusterInstallReconciler) validatePullSecret(pullSecret string) error {
 	var s imagePullSecret
@@ -1302,13 +1766,17 @@ func (r *ImageClusterInstallReconciler) handleFinalizer(ctx context.Context, log
 		return nil
 	}
 
+	if r.ImageBuilder != nil {
+		r.ImageBuilder.Forget(string(ici.UID))
+	}
+
 	lockDir, _, err := r.configDirs(ici)
 	if err != nil {
 		return ctrl.Result{}, true, err
 	}
 
 	if _, err := os.Stat(lockDir); err == nil {
-		locked, lockErr, funcErr := filelock.WithWriteLock(lockDir, func() error {
+		locked, lockErr, funcErr := filelock.WithWriteLockContext(ctx, lockDir, r.lockOptions(ctx, log), func() error {
 			log.Info("removing files for image cluster install")
 			return os.RemoveAll(lockDir)
 		})
@@ -1344,6 +1812,44 @@ func (r *ImageClusterInstallReconciler) handleFinalizer(ctx context.Context, log
 	return ctrl.Result{}, true, removeFinalizer()
 }
 
+// removeExpiredImage removes the configuration image of an installed cluster once the ISO retention of the operator
+// config has passed since the installation completed, the auth files are kept
+func (r *ImageClusterInstallReconciler) removeExpiredImage(ctx context.Context, log logrus.FieldLogger, ici *v1alpha1.ImageClusterInstall) (ctrl.Result, error) {
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	retention := config.isoRetention()
+	cond := findCondition(ici.Status.Conditions, hivev1.ClusterInstallCompleted)
+	if retention == 0 || cond == nil {
+		return ctrl.Result{}, nil
+	}
+	if remaining := time.Until(cond.LastTransitionTime.Add(retention)); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	lockDir := filepath.Join(r.Options.DataDir, "namespaces", ici.Namespace, string(ici.UID))
+	isoPath := filepath.Join(GetClusterConfigDir(filepath.Join(r.Options.DataDir, "namespaces"), ici.Namespace, string(ici.UID)), IsoName)
+	if !fileExists(isoPath) {
+		return ctrl.Result{}, nil
+	}
+	locked, lockErr, funcErr := filelock.WithWriteLockContext(ctx, lockDir, r.lockOptions(ctx, log), func() error {
+		log.Infof("Removing the configuration image, the ISO retention of %s has passed", retention)
+		return os.Remove(isoPath)
+	})
+	if lockErr != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to acquire file lock: %w", lockErr)
+	}
+	if funcErr != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove configuration image: %w", funcErr)
+	}
+	if !locked {
+		log.Info("requeueing due to lock contention")
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
 func (r *ImageClusterInstallReconciler) writeInvokerCM(filePath string) error {
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
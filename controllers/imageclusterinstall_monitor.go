@@ -23,13 +23,19 @@ import (
 	_ "crypto/sha256"
 	_ "crypto/sha512"
 	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,14 +58,19 @@ type ImageClusterInstallMonitor struct {
 	Scheme                       *runtime.Scheme
 	DefaultInstallTimeout        time.Duration
 	GetSpokeClusterInstallStatus monitor.GetInstallStatusFunc
+	GetReadinessGatesStatus      monitor.GetReadinessGatesStatusFunc
+	ApplyPostInstallManifest     monitor.ApplyManifestFunc
+	CollectSpokeDiagnostics      monitor.CollectDiagnosticsFunc
 	Options                      *ImageClusterInstallReconcilerOptions
 }
 
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imageclusterinstalls,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imageclusterinstalls/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=metal3.io,resources=baremetalhosts,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=metal3.io,resources=dataimages,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imagebasedinstalloperatorconfigs,verbs=get;list;watch
 
 func (r *ImageClusterInstallMonitor) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := r.Log.WithFields(logrus.Fields{"name": req.Name, "namespace": req.Namespace})
@@ -75,19 +86,44 @@ func (r *ImageClusterInstallMonitor) Reconcile(ctx context.Context, req ctrl.Req
 	if ici.Status.BootTime.IsZero() {
 		return ctrl.Result{}, nil
 	}
-	// Nothing to do if the installation process has already stopped
+	// Only the post-install manifests and health are left once the installation process has stopped
 	if InstallationCompleted(ici) {
-		log.Infof("Cluster %s/%s finished installation process, nothing to do", ici.Namespace, ici.Name)
-		return ctrl.Result{}, nil
+		log.Infof("Cluster %s/%s finished installation process", ici.Namespace, ici.Name)
+		return r.monitorInstalledCluster(ctx, log, ici)
 	}
 	return r.monitorInstallationProgress(ctx, log, ici)
 }
 
+// monitorInstalledCluster applies the post-install manifests the installation didn't wait for and watches the health
+// of the installed cluster
+func (r *ImageClusterInstallMonitor) monitorInstalledCluster(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall) (ctrl.Result, error) {
+
+	retryAfter, err := r.applyRemainingPostInstallManifests(ctx, log, ici)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	res, err := r.monitorClusterHealth(ctx, log, ici)
+	if err != nil {
+		return res, err
+	}
+	if retryAfter > 0 && (res.RequeueAfter == 0 || retryAfter < res.RequeueAfter) {
+		res.RequeueAfter = retryAfter
+	}
+	return res, nil
+}
+
 func (r *ImageClusterInstallMonitor) monitorInstallationProgress(
 	ctx context.Context,
 	log logrus.FieldLogger,
 	ici *v1alpha1.ImageClusterInstall) (ctrl.Result, error) {
 
+	config, err := getOperatorConfig(ctx, r.Client, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	bmh, err := getBMH(ctx, r.Client, ici.Status.BareMetalHostRef)
 	if err != nil {
 		log.WithError(err).Error("failed to get BareMetalHost")
@@ -95,21 +131,21 @@ func (r *ImageClusterInstallMonitor) monitorInstallationProgress(
 	}
 	if !bmh.Status.PoweredOn {
 		log.Infof("BareMetalHost %s/%s is not powered on yet", bmh.Name, bmh.Namespace)
-		timedout, err := r.handleClusterTimeout(ctx, log, ici, r.DefaultInstallTimeout)
+		timedout, err := r.handleClusterTimeout(ctx, log, ici, config, powerOnPhase)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		if timedout {
-			log.Infof("BareMetalHost %s/%s failed to power on within the cluster installation timeout", bmh.Name, bmh.Namespace)
+			log.Infof("BareMetalHost %s/%s failed to power on within the timeout", bmh.Name, bmh.Namespace)
 			// in case of timeout we want to requeue after 1 hour
 			return ctrl.Result{RequeueAfter: time.Hour}, nil
 		}
 		if err := r.setClusterInstallingConditions(ctx, ici, "Waiting for BMH to power on"); err != nil {
 			log.WithError(err).Error("failed to set installing conditions")
 		}
-		return ctrl.Result{RequeueAfter: time.Minute}, nil
+		return ctrl.Result{RequeueAfter: config.installProgressInterval()}, nil
 	}
-	res, err := r.checkClusterStatus(ctx, log, ici, bmh)
+	res, err := r.checkClusterStatus(ctx, log, ici, bmh, config)
 	if err != nil {
 		log.WithError(err).Error("failed to check cluster status")
 		return ctrl.Result{}, err
@@ -117,13 +153,72 @@ func (r *ImageClusterInstallMonitor) monitorInstallationProgress(
 	return res, nil
 }
 
-func (r *ImageClusterInstallMonitor) handleClusterTimeout(ctx context.Context, log logrus.FieldLogger, ici *v1alpha1.ImageClusterInstall, defaultTimeout time.Duration) (bool, error) {
-	timeout := defaultTimeout
+// installPhase is a phase of the installation that can time out on its own
+type installPhase struct {
+	// reason is set on the conditions when the phase times out
+	reason string
+	// failure describes the phase timing out
+	failure string
+	timeout func(*v1alpha1.PhaseTimeouts) *metav1.Duration
+}
+
+var (
+	powerOnPhase = installPhase{
+		reason:  v1alpha1.HostPowerOnTimedoutReason,
+		failure: "Host failed to power on",
+		timeout: func(t *v1alpha1.PhaseTimeouts) *metav1.Duration { return t.PowerOn },
+	}
+	configImageAttachedPhase = installPhase{
+		reason:  v1alpha1.ConfigImageAttachTimedoutReason,
+		failure: "Configuration image failed to be attached to the host",
+		timeout: func(t *v1alpha1.PhaseTimeouts) *metav1.Duration { return t.ConfigImageAttached },
+	}
+	spokeAPIReachablePhase = installPhase{
+		reason:  v1alpha1.SpokeAPIReachableTimedoutReason,
+		failure: "Cluster API failed to become reachable",
+		timeout: func(t *v1alpha1.PhaseTimeouts) *metav1.Duration { return t.SpokeAPIReachable },
+	}
+	clusterConvergedPhase = installPhase{
+		reason:  v1alpha1.ClusterConvergenceTimedoutReason,
+		failure: "Cluster failed to converge",
+		timeout: func(t *v1alpha1.PhaseTimeouts) *metav1.Duration { return t.ClusterConverged },
+	}
+)
+
+// phaseTimeout returns the timeout of phase set in the ImageClusterInstall or else in the operator config, zero when
+// neither sets one
+func phaseTimeout(ici *v1alpha1.ImageClusterInstall, config *operatorConfig, phase installPhase) time.Duration {
+	if ici.Spec.Timeouts != nil {
+		if timeout := phase.timeout(ici.Spec.Timeouts); timeout != nil {
+			return timeout.Duration
+		}
+	}
+	phases := config.phaseTimeouts()
+	return durationOrDefault(phase.timeout(&phases), 0)
+}
+
+// handleClusterTimeout checks the timeout of the current phase and the install timeout, both are measured from the
+// time the host was requested to boot
+func (r *ImageClusterInstallMonitor) handleClusterTimeout(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	config *operatorConfig,
+	phase installPhase) (bool, error) {
 
 	if installationTimedout(ici) {
 		return true, nil
 	}
 
+	if timeout := phaseTimeout(ici, config, phase); timeout > 0 && ici.Status.BootTime.Add(timeout).Before(time.Now()) {
+		message := fmt.Sprintf("%s within the timeout (%s)", phase.failure, timeout)
+		if err := r.setClusterTimeoutConditions(ctx, ici, phase.reason, message); err != nil {
+			log.WithError(err).Error("failed to set cluster timeout conditions")
+		}
+		return true, nil
+	}
+
+	timeout := config.installTimeout(r.DefaultInstallTimeout)
 	if timeoutOverride, present := ici.Annotations[installTimeoutAnnotation]; present {
 		var err error
 		timeout, err = time.ParseDuration(timeoutOverride)
@@ -133,7 +228,8 @@ func (r *ImageClusterInstallMonitor) handleClusterTimeout(ctx context.Context, l
 	}
 
 	if ici.Status.BootTime.Add(timeout).Before(time.Now()) {
-		err := r.setClusterTimeoutConditions(ctx, ici, timeout.String())
+		message := fmt.Sprintf("Cluster failed to install within the timeout (%s)", timeout)
+		err := r.setClusterTimeoutConditions(ctx, ici, v1alpha1.InstallTimedoutReason, message)
 		if err != nil {
 			log.WithError(err).Error("failed to set cluster timeout conditions")
 		}
@@ -145,7 +241,8 @@ func (r *ImageClusterInstallMonitor) handleClusterTimeout(ctx context.Context, l
 func (r *ImageClusterInstallMonitor) checkClusterStatus(ctx context.Context,
 	log logrus.FieldLogger,
 	ici *v1alpha1.ImageClusterInstall,
-	bmh *bmh_v1alpha1.BareMetalHost) (ctrl.Result, error) {
+	bmh *bmh_v1alpha1.BareMetalHost,
+	config *operatorConfig) (ctrl.Result, error) {
 	bmhRef := types.NamespacedName{Name: bmh.Name, Namespace: bmh.Namespace}
 
 	res, stop, err := r.handleDataImageDeletion(ctx, log, ici, bmhRef)
@@ -159,21 +256,48 @@ func (r *ImageClusterInstallMonitor) checkClusterStatus(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
-	status := r.GetSpokeClusterInstallStatus(ctx, log, spokeClient)
+	status, err := r.spokeInstallStatus(ctx, log, ici, spokeClient)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 	if !status.Installed {
-		timedout, err := r.handleClusterTimeout(ctx, log, ici, r.DefaultInstallTimeout)
+		phase := clusterConvergedPhase
+		// the API should answer once the host booted the installed cluster from the attached configuration image
+		unreachable := false
+		if !status.APIReachable {
+			attached, err := r.configImageAttached(ctx, bmhRef)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			phase = configImageAttachedPhase
+			if attached {
+				phase = spokeAPIReachablePhase
+				unreachable = true
+			}
+		}
+		timedout, err := r.handleClusterTimeout(ctx, log, ici, config, phase)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		if timedout {
+			if status.APIReachable {
+				r.collectDiagnostics(ctx, log, ici, spokeClient)
+			}
 			// in case of timeout we want to requeue after 1 hour
 			return ctrl.Result{RequeueAfter: time.Hour}, nil
 		}
-		log.Infof("cluster install in progress: %s", status.String())
-		if err := r.setClusterInstallingConditions(ctx, ici, status.String()); err != nil {
+		if unreachable {
+			message := fmt.Sprintf("Cluster API is unreachable: %s", status.ClusterVersionStatus)
+			log.Info(message)
+			err = r.setClusterInstallingConditionsWithReason(ctx, ici, v1alpha1.ClusterUnreachableReason, message)
+		} else {
+			log.Infof("cluster install in progress: %s", status.String())
+			err = r.setClusterInstallingConditions(ctx, ici, status.String())
+		}
+		if err != nil {
 			log.WithError(err).Error("failed to set installing conditions")
 		}
-		return ctrl.Result{RequeueAfter: time.Minute}, nil
+		return ctrl.Result{RequeueAfter: config.installProgressInterval()}, nil
 	}
 	log.Info("cluster is installed, making sure DataImage is removed")
 
@@ -186,6 +310,31 @@ func (r *ImageClusterInstallMonitor) checkClusterStatus(ctx context.Context,
 		return res, err
 	}
 
+	if ici.Spec.WaitForPostInstallManifests {
+		applied, retryAfter, err := r.applyPostInstallManifests(ctx, log, ici, spokeClient)
+		if err != nil {
+			log.WithError(err).Error("failed to apply post-install manifests")
+			return ctrl.Result{}, err
+		}
+		if !applied {
+			timedout, err := r.handleClusterTimeout(ctx, log, ici, config, clusterConvergedPhase)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if timedout {
+				r.collectDiagnostics(ctx, log, ici, spokeClient)
+				// in case of timeout we want to requeue after 1 hour
+				return ctrl.Result{RequeueAfter: time.Hour}, nil
+			}
+			message := postInstallManifestsMessage(ici.Status.PostInstallManifests)
+			log.Info(message)
+			if err := r.setClusterInstallingConditions(ctx, ici, message); err != nil {
+				log.WithError(err).Error("failed to set installing conditions")
+			}
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+	}
+
 	if err := r.setClusterInstalledConditions(ctx, ici); err != nil {
 		log.WithError(err).Error("failed to set installed conditions")
 		return ctrl.Result{}, err
@@ -209,6 +358,48 @@ func (r *ImageClusterInstallMonitor) handleDataImageDeletion(ctx context.Context
 	return ctrl.Result{}, false, nil
 }
 
+// spokeInstallStatus returns the installation status of the spoke cluster. Once its cluster version and nodes are
+// ready, the cluster is only installed when all the readiness gates pass, the pending ones are recorded in the status.
+func (r *ImageClusterInstallMonitor) spokeInstallStatus(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	spokeClient client.Client) (monitor.ClusterInstallStatus, error) {
+
+	status := r.GetSpokeClusterInstallStatus(ctx, log, spokeClient)
+	if !status.Installed || len(ici.Spec.ReadinessGates) == 0 {
+		return status, nil
+	}
+
+	pending := r.GetReadinessGatesStatus(ctx, log, spokeClient, ici.Spec.ReadinessGates)
+	if len(pending) > 0 {
+		status.Installed = false
+		status.ReadinessGatesStatus = fmt.Sprintf("%d of %d readiness gates pending: %s",
+			len(pending), len(ici.Spec.ReadinessGates), strings.Join(pending, "; "))
+	}
+	if slices.Equal(pending, ici.Status.PendingReadinessGates) {
+		return status, nil
+	}
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Status.PendingReadinessGates = pending
+	if err := r.Status().Patch(ctx, ici, patch); err != nil {
+		return status, fmt.Errorf("failed to update the pending readiness gates: %w", err)
+	}
+	return status, nil
+}
+
+// configImageAttached returns true when the DataImage of the host reports the configuration image as attached
+func (r *ImageClusterInstallMonitor) configImageAttached(ctx context.Context, bmhRef types.NamespacedName) (bool, error) {
+	dataImage, err := getDataImage(ctx, r.Client, bmhRef.Namespace, bmhRef.Name)
+	if err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get DataImage %s/%s: %w", bmhRef.Namespace, bmhRef.Name, err)
+	}
+	return dataImage.Status.AttachedImage.URL != "", nil
+}
+
 func (r *ImageClusterInstallMonitor) spokeClient(ctx context.Context, ici *v1alpha1.ImageClusterInstall) (client.Client, error) {
 	if ici.Spec.ClusterMetadata == nil || ici.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name == "" {
 		return nil, fmt.Errorf("kubeconfig secret must be set to get spoke client")
@@ -242,6 +433,11 @@ func (r *ImageClusterInstallMonitor) spokeClient(ctx context.Context, ici *v1alp
 		return nil, fmt.Errorf("failed to get restconfig for kube client: %w", err)
 	}
 	restConfig.Timeout = 10 * time.Second
+	if ici.Spec.APIEndpointOverride != "" {
+		if err := overrideAPIEndpoint(restConfig, ici.Spec.APIEndpointOverride); err != nil {
+			return nil, err
+		}
+	}
 
 	var schemes = runtime.NewScheme()
 	utilruntime.Must(corev1.AddToScheme(schemes))
@@ -255,6 +451,33 @@ func (r *ImageClusterInstallMonitor) spokeClient(ctx context.Context, ici *v1alp
 	return spokeClient, nil
 }
 
+// overrideAPIEndpoint makes restConfig dial endpoint instead of the host of its server, the certificate of the server is
+// still validated against that host. The port of the server is kept when endpoint doesn't set one.
+func overrideAPIEndpoint(restConfig *rest.Config, endpoint string) error {
+	server, err := url.Parse(restConfig.Host)
+	if err != nil {
+		return fmt.Errorf("failed to parse API server URL %s: %w", restConfig.Host, err)
+	}
+	if restConfig.TLSClientConfig.ServerName == "" {
+		restConfig.TLSClientConfig.ServerName = server.Hostname()
+	}
+
+	host, port := v1alpha1.SplitAPIEndpoint(endpoint)
+	if port == "" {
+		port = server.Port()
+	}
+	switch {
+	case port != "":
+		server.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		server.Host = "[" + host + "]"
+	default:
+		server.Host = host
+	}
+	restConfig.Host = server.String()
+	return nil
+}
+
 func (r *ImageClusterInstallMonitor) SetupWithManager(mgr ctrl.Manager) error {
 	// Predicate that check if BootTime is initialized
 	bootTimeInitialized := predicate.Funcs{
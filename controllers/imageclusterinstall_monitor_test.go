@@ -10,6 +10,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -59,8 +60,10 @@ var _ = Describe("Monitor", func() {
 		r = &ImageClusterInstallMonitor{
 			Client:                       c,
 			Log:                          logrus.New(),
+			Scheme:                       scheme.Scheme,
 			DefaultInstallTimeout:        time.Hour,
 			GetSpokeClusterInstallStatus: monitor.SuccessMonitor,
+			CollectSpokeDiagnostics:      monitor.StaticDiagnostics,
 		}
 
 		imageSet := &hivev1.ClusterImageSet{
@@ -222,6 +225,244 @@ var _ = Describe("Monitor", func() {
 		Expect(bmh.ObjectMeta.ResourceVersion).To(Equal(resourceVersion))
 	})
 
+	It("watches the health of an installed cluster during its post-install health window", func() {
+		r.GetSpokeClusterInstallStatus = monitor.SuccessMonitor
+		config := &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{
+				RequeueIntervals: &v1alpha1.RequeueIntervals{InstallProgress: &metav1.Duration{Duration: time.Nanosecond}},
+			},
+		}
+		Expect(c.Create(ctx, config)).To(Succeed())
+		clusterInstall.Spec.PostInstallHealthWindow = &metav1.Duration{Duration: time.Hour}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(time.Nanosecond))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, v1alpha1.ClusterHealthyCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterHealthyReason))
+		Expect(clusterInstall.Status.LastHealthyTime).NotTo(BeNil())
+		lastHealthyTime := *clusterInstall.Status.LastHealthyTime
+
+		By("Reporting an unhealthy cluster without reopening the install conditions")
+		r.GetSpokeClusterInstallStatus = monitor.FailureMonitor
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(time.Nanosecond))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond = findCondition(clusterInstall.Status.Conditions, v1alpha1.ClusterHealthyCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterUnhealthyReason))
+		Expect(clusterInstall.Status.LastHealthyTime.Equal(&lastHealthyTime)).To(BeTrue())
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+
+		By("Reporting an unreachable cluster")
+		r.GetSpokeClusterInstallStatus = monitor.UnreachableMonitor
+		_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond = findCondition(clusterInstall.Status.Conditions, v1alpha1.ClusterHealthyCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionUnknown))
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterUnreachableReason))
+	})
+
+	It("stops watching the health of an installed cluster after its post-install health window", func() {
+		clusterInstall.Spec.PostInstallHealthWindow = &metav1.Duration{Duration: time.Hour}
+		clusterInstall.Status.Conditions = []hivev1.ClusterInstallCondition{{
+			Type:               hivev1.ClusterInstallCompleted,
+			Status:             corev1.ConditionTrue,
+			Reason:             v1alpha1.InstallSucceededReason,
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-2 * time.Hour)),
+		}}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, v1alpha1.ClusterHealthyCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionUnknown))
+		Expect(cond.Reason).To(Equal(v1alpha1.HealthWatchEndedReason))
+		Expect(clusterInstall.Status.LastHealthyTime).To(BeNil())
+	})
+
+	It("waits for the readiness gates before reporting cluster installed", func() {
+		r.GetSpokeClusterInstallStatus = monitor.SuccessMonitor
+		r.GetReadinessGatesStatus = monitor.PendingReadinessGates
+		clusterInstall.Spec.ReadinessGates = []v1alpha1.ReadinessGate{{
+			APIVersion:    "apps/v1",
+			Kind:          "Deployment",
+			Namespace:     "openshift-ptp",
+			Name:          "ptp-operator",
+			ConditionType: "Available",
+		}}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(clusterInstall.Status.PendingReadinessGates).To(Equal([]string{"Deployment openshift-ptp/ptp-operator: not found"}))
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.InstallInProgressReason))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallStopped)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Message).To(ContainSubstring("1 of 1 readiness gates pending: Deployment openshift-ptp/ptp-operator: not found"))
+
+		By("Reporting cluster installed once the gates pass")
+		r.GetReadinessGatesStatus = monitor.PassingReadinessGates
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(clusterInstall.Status.PendingReadinessGates).To(BeEmpty())
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("waits for the post-install manifests before reporting cluster installed", func() {
+		r.GetSpokeClusterInstallStatus = monitor.SuccessMonitor
+		r.ApplyPostInstallManifest = monitor.FailedManifest
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "day2", Namespace: clusterInstallNamespace},
+			Data: map[string]string{
+				"b-config.yaml": "apiVersion: ptp.openshift.io/v1\nkind: PtpConfig\nmetadata:\n  name: grandmaster\n  namespace: openshift-ptp\n",
+				"a-operator.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: openshift-ptp\n---\n" +
+					"apiVersion: operators.coreos.com/v1alpha1\nkind: Subscription\nmetadata:\n  name: ptp-operator\n  namespace: openshift-ptp\n",
+			},
+		})).To(Succeed())
+		clusterInstall.Spec.PostInstallManifestsRefs = []corev1.LocalObjectReference{{Name: "day2"}}
+		clusterInstall.Spec.WaitForPostInstallManifests = true
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: postInstallManifestInitialBackoff}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		manifests := clusterInstall.Status.PostInstallManifests
+		Expect(manifests).To(HaveLen(3))
+		Expect(postInstallManifestName(manifests[0])).To(Equal("Namespace openshift-ptp"))
+		Expect(manifests[0].State).To(Equal(v1alpha1.PostInstallManifestFailed))
+		Expect(manifests[0].Attempts).To(Equal(int32(1)))
+		Expect(manifests[0].Message).To(ContainSubstring(`no matches for kind "Namespace"`))
+		Expect(postInstallManifestName(manifests[1])).To(Equal("Subscription openshift-ptp/ptp-operator"))
+		Expect(manifests[1].State).To(Equal(v1alpha1.PostInstallManifestPending))
+		Expect(postInstallManifestName(manifests[2])).To(Equal("PtpConfig openshift-ptp/grandmaster"))
+		Expect(manifests[2].State).To(Equal(v1alpha1.PostInstallManifestPending))
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallStopped)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Message).To(Equal(`Waiting for post-install manifests: 0 of 3 applied, Namespace openshift-ptp failed to apply: no matches for kind "Namespace" in version "v1"`))
+
+		By("Waiting for the backoff before applying again")
+		r.ApplyPostInstallManifest = monitor.AppliedManifest
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(res.RequeueAfter).To(BeNumerically("<=", postInstallManifestInitialBackoff))
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(clusterInstall.Status.PostInstallManifests[0].Attempts).To(Equal(int32(1)))
+
+		By("Reporting cluster installed once they are all applied")
+		patch := client.MergeFrom(clusterInstall.DeepCopy())
+		clusterInstall.Status.PostInstallManifests[0].LastAttemptTime = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		Expect(c.Status().Patch(ctx, clusterInstall, patch)).To(Succeed())
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		for _, manifest := range clusterInstall.Status.PostInstallManifests {
+			Expect(manifest.State).To(Equal(v1alpha1.PostInstallManifestApplied))
+			Expect(manifest.Message).To(BeEmpty())
+		}
+		Expect(clusterInstall.Status.PostInstallManifests[0].Attempts).To(Equal(int32(2)))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+	})
+
+	It("applies the post-install manifests after reporting cluster installed", func() {
+		r.GetSpokeClusterInstallStatus = monitor.SuccessMonitor
+		r.ApplyPostInstallManifest = monitor.AppliedManifest
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "day2", Namespace: clusterInstallNamespace},
+			Data: map[string]string{
+				"operator.yaml": "apiVersion: operators.coreos.com/v1alpha1\nkind: Subscription\nmetadata:\n  name: ptp-operator\n  namespace: openshift-ptp\n",
+			},
+		})).To(Succeed())
+		clusterInstall.Spec.PostInstallManifestsRefs = []corev1.LocalObjectReference{{Name: "day2"}}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(InstallationCompleted(clusterInstall)).To(BeTrue())
+		Expect(clusterInstall.Status.PostInstallManifests).To(BeEmpty())
+
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(clusterInstall.Status.PostInstallManifests).To(HaveLen(1))
+		Expect(clusterInstall.Status.PostInstallManifests[0].State).To(Equal(v1alpha1.PostInstallManifestApplied))
+	})
+
 	It("waits for DataImage deletion before reporting cluster installed", func() {
 		r.GetSpokeClusterInstallStatus = monitor.SuccessMonitor
 		dataImage := &bmh_v1alpha1.DataImage{
@@ -335,6 +576,239 @@ var _ = Describe("Monitor", func() {
 		Expect(clusterInstall.ObjectMeta.ResourceVersion).To(Equal(resourceVersion))
 	})
 
+	It("uses the install timeout and progress interval of the operator config", func() {
+		r.GetSpokeClusterInstallStatus = monitor.FailureMonitor
+		config := &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{
+				RequeueIntervals: &v1alpha1.RequeueIntervals{InstallProgress: &metav1.Duration{Duration: 10 * time.Second}},
+			},
+		}
+		Expect(c.Create(ctx, config)).To(Succeed())
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res.RequeueAfter).To(Equal(10 * time.Second))
+
+		config.Spec.Timeouts = &v1alpha1.OperatorTimeouts{Install: &metav1.Duration{Duration: time.Nanosecond}}
+		Expect(c.Update(ctx, config)).To(Succeed())
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.InstallTimedoutReason))
+	})
+
+	It("sets the power on timeout reason when the host doesn't power on within its timeout", func() {
+		bmh.Status.PoweredOn = false
+		Expect(c.Update(ctx, bmh)).To(Succeed())
+		clusterInstall.Spec.Timeouts = &v1alpha1.PhaseTimeouts{PowerOn: &metav1.Duration{Duration: time.Nanosecond}}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.HostPowerOnTimedoutReason))
+		Expect(cond.Message).To(Equal("Host failed to power on within the timeout (1ns)"))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.HostPowerOnTimedoutReason))
+	})
+
+	It("reports the cluster unreachable once the configuration image is attached", func() {
+		r.GetSpokeClusterInstallStatus = monitor.UnreachableMonitor
+		Expect(c.Create(ctx, &bmh_v1alpha1.DataImage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bmh.Name,
+				Namespace: bmh.Namespace,
+			},
+			Spec: bmh_v1alpha1.DataImageSpec{
+				URL: "https://example.com/config.iso",
+			},
+			Status: bmh_v1alpha1.DataImageStatus{
+				AttachedImage: bmh_v1alpha1.AttachedImageReference{URL: "https://example.com/config.iso"},
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Minute}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallCompleted)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterUnreachableReason))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallStopped)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterUnreachableReason))
+		Expect(cond.Message).To(Equal("Cluster API is unreachable: Failed to get ibi-monitor-cm : connection refused"))
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(corev1.ConditionFalse))
+	})
+
+	It("uses the phase timeouts of the operator config until the spoke API is reachable", func() {
+		r.GetSpokeClusterInstallStatus = monitor.UnreachableMonitor
+		config := &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec: v1alpha1.ImageBasedInstallOperatorConfigSpec{
+				Timeouts: &v1alpha1.OperatorTimeouts{PhaseTimeouts: v1alpha1.PhaseTimeouts{
+					ConfigImageAttached: &metav1.Duration{Duration: time.Nanosecond},
+					SpokeAPIReachable:   &metav1.Duration{Duration: 2 * time.Nanosecond},
+				}},
+			},
+		}
+		Expect(c.Create(ctx, config)).To(Succeed())
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.ConfigImageAttachTimedoutReason))
+
+		By("Reporting the spoke API once the configuration image is attached")
+		clusterInstall.Status.Conditions = nil
+		Expect(c.Status().Update(ctx, clusterInstall)).To(Succeed())
+		dataImage := &bmh_v1alpha1.DataImage{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bmh.Name,
+				Namespace: bmh.Namespace,
+			},
+			Spec: bmh_v1alpha1.DataImageSpec{
+				URL: "https://example.com/config.iso",
+			},
+			Status: bmh_v1alpha1.DataImageStatus{
+				AttachedImage: bmh_v1alpha1.AttachedImageReference{URL: "https://example.com/config.iso"},
+			},
+		}
+		Expect(c.Create(ctx, dataImage)).To(Succeed())
+
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond = findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.SpokeAPIReachableTimedoutReason))
+	})
+
+	It("sets the convergence timeout reason when the reachable cluster doesn't converge within its timeout", func() {
+		r.GetSpokeClusterInstallStatus = monitor.FailureMonitor
+		clusterInstall.Spec.Timeouts = &v1alpha1.PhaseTimeouts{
+			PowerOn:          &metav1.Duration{Duration: time.Nanosecond},
+			ClusterConverged: &metav1.Duration{Duration: 2 * time.Nanosecond},
+		}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.ClusterConvergenceTimedoutReason))
+		Expect(cond.Message).To(Equal("Cluster failed to converge within the timeout (2ns)"))
+	})
+
+	It("collects diagnostics once from the reachable cluster when the installation times out", func() {
+		r.GetSpokeClusterInstallStatus = monitor.FailureMonitor
+		clusterInstall.Spec.Timeouts = &v1alpha1.PhaseTimeouts{ClusterConverged: &metav1.Duration{Duration: time.Nanosecond}}
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		diagnostics := clusterInstall.Status.Diagnostics
+		Expect(diagnostics).NotTo(BeNil())
+		Expect(diagnostics.ConfigMapRef.Name).To(Equal(DiagnosticsConfigMapName(clusterInstall)))
+		Expect(diagnostics.Reason).To(Equal(v1alpha1.ClusterConvergenceTimedoutReason))
+		Expect(diagnostics.Truncated).To(BeFalse())
+		cm := &corev1.ConfigMap{}
+		Expect(c.Get(ctx, types.NamespacedName{Namespace: clusterInstallNamespace, Name: diagnostics.ConfigMapRef.Name}, cm)).To(Succeed())
+		Expect(cm.OwnerReferences).To(HaveLen(1))
+		Expect(cm.OwnerReferences[0].UID).To(Equal(clusterInstall.UID))
+		Expect(cm.Data).To(HaveKeyWithValue(monitor.DiagnosticsNodesKey, "- name: node1\n"))
+
+		By("Not collecting them again for the same installation")
+		r.CollectSpokeDiagnostics = func(_ context.Context, _ logrus.FieldLogger, _ client.Client) map[string]string {
+			Fail("diagnostics collected twice")
+			return nil
+		}
+		res, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+	})
+
+	It("doesn't collect diagnostics from an unreachable cluster", func() {
+		r.GetSpokeClusterInstallStatus = monitor.UnreachableMonitor
+		r.DefaultInstallTimeout = -time.Minute
+		Expect(c.Create(ctx, clusterInstall)).To(Succeed())
+		Expect(c.Create(ctx, clusterDeployment)).To(Succeed())
+
+		key := types.NamespacedName{
+			Namespace: clusterInstallNamespace,
+			Name:      clusterInstallName,
+		}
+		res, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res).To(Equal(ctrl.Result{RequeueAfter: time.Hour}))
+
+		Expect(c.Get(ctx, key, clusterInstall)).To(Succeed())
+		Expect(clusterInstall.Status.Diagnostics).To(BeNil())
+		cond := findCondition(clusterInstall.Status.Conditions, hivev1.ClusterInstallFailed)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal(v1alpha1.InstallTimedoutReason))
+	})
+
 	It("sets conditions to cluster timeout when the default timeout has passed", func() {
 		// set negative timeout to ensure it triggers and so that no time is wasted in tests
 		r.DefaultInstallTimeout = -time.Minute
@@ -573,3 +1047,32 @@ var _ = Describe("Monitor", func() {
 		Expect(cond.Message).To(Equal(v1alpha1.InstallSucceededMessage))
 	})
 })
+
+var _ = Describe("overrideAPIEndpoint", func() {
+	It("dials the endpoint and validates the certificate against the original host", func() {
+		for _, tc := range []struct {
+			endpoint string
+			host     string
+		}{
+			{"192.0.2.10", "https://192.0.2.10:6443"},
+			{"192.0.2.10:443", "https://192.0.2.10:443"},
+			{"2001:db8::10", "https://[2001:db8::10]:6443"},
+			{"[2001:db8::10]:443", "https://[2001:db8::10]:443"},
+		} {
+			restConfig := &rest.Config{Host: "https://api.test-cluster.example.com:6443"}
+			Expect(overrideAPIEndpoint(restConfig, tc.endpoint)).To(Succeed())
+			Expect(restConfig.Host).To(Equal(tc.host))
+			Expect(restConfig.TLSClientConfig.ServerName).To(Equal("api.test-cluster.example.com"))
+		}
+	})
+
+	It("keeps the server name set in the kubeconfig", func() {
+		restConfig := &rest.Config{
+			Host:            "https://api.test-cluster.example.com:6443",
+			TLSClientConfig: rest.TLSClientConfig{ServerName: "kubernetes.default"},
+		}
+		Expect(overrideAPIEndpoint(restConfig, "192.0.2.10")).To(Succeed())
+		Expect(restConfig.Host).To(Equal("https://192.0.2.10:6443"))
+		Expect(restConfig.TLSClientConfig.ServerName).To(Equal("kubernetes.default"))
+	})
+})
@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/internal/installer"
+)
+
+const (
+	// referencedSecretsIndex and referencedConfigMapsIndex are the field indexes of ImageClusterInstalls by the names
+	// of the Secrets and ConfigMaps in their namespace that their configuration image is built from
+	referencedSecretsIndex    = ".spec.referencedSecrets"
+	referencedConfigMapsIndex = ".spec.referencedConfigMaps"
+)
+
+// imageInputs are the inputs of the configuration image that are read from objects other than the
+// ImageClusterInstall. The image is rebuilt when they change until the installation starts.
+type imageInputs struct {
+	NetworkConfig  string                 `json:"networkConfig,omitempty"`
+	SecretValues   installer.SecretValues `json:"secretValues"`
+	CABundle       string                 `json:"caBundle,omitempty"`
+	ExtraManifests string                 `json:"extraManifests,omitempty"`
+}
+
+// imageInputsHash returns the hash of the current tracked inputs of the configuration image
+func (r *ImageClusterInstallReconciler) imageInputsHash(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost) (string, error) {
+
+	nmstate, err := r.nmstateConfig(ctx, ici, bmh)
+	if err != nil {
+		return "", err
+	}
+
+	secretValues, err := r.getSecretValues(ctx, ici)
+	if err != nil {
+		return "", err
+	}
+
+	caBundle, err := r.getCABundle(ctx, ici)
+	if err != nil {
+		return "", err
+	}
+
+	manifests, err := r.extraManifests(ctx, ici, cd, bmh)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(imageInputs{
+		NetworkConfig:  nmstate,
+		SecretValues:   secretValues,
+		CABundle:       caBundle,
+		ExtraManifests: extraManifestHashes(manifests),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// imageUpToDate returns true when the image in isoWorkDir exists and was built from inputsHash.
+// Images of installations that started, and images built before the inputs were tracked, are never rebuilt.
+func imageUpToDate(ici *v1alpha1.ImageClusterInstall, isoWorkDir, inputsHash string) bool {
+	if !verifyIsoAndAuthExists(isoWorkDir) {
+		return false
+	}
+	if ici.Status.BareMetalHostRef != nil {
+		return true
+	}
+	built, err := os.ReadFile(filepath.Join(isoWorkDir, imageInputsFile))
+	if err != nil {
+		return true
+	}
+	return string(built) == inputsHash
+}
+
+// referencedInputs returns the names of the Secrets and of the ConfigMaps in the ImageClusterInstall namespace that its
+// configuration image is built from
+func referencedInputs(ici *v1alpha1.ImageClusterInstall) ([]string, []string) {
+	secrets, configMaps := []string{}, []string{}
+	for _, source := range caBundleSources(ici) {
+		if v1alpha1.CABundleSourceKind(source) == v1alpha1.CABundleSourceKindSecret {
+			secrets = append(secrets, source.Name)
+		} else {
+			configMaps = append(configMaps, source.Name)
+		}
+	}
+	for _, ref := range ici.Spec.ExtraManifestsRefs {
+		configMaps = append(configMaps, ref.Name)
+	}
+	for _, ref := range ici.Spec.ExtraManifestsSecretRefs {
+		secrets = append(secrets, ref.Name)
+	}
+	for _, ref := range []*corev1.LocalObjectReference{ici.Spec.SSHKeysSecretRef, proxySecretRef(ici)} {
+		if ref != nil {
+			secrets = append(secrets, ref.Name)
+		}
+	}
+	if ref := ici.Spec.NetworkConfigRef; ref != nil {
+		if ref.Kind == v1alpha1.NetworkConfigKindConfigMap {
+			configMaps = append(configMaps, ref.Name)
+		} else {
+			secrets = append(secrets, ref.Name)
+		}
+	}
+	return uniqueSorted(secrets), uniqueSorted(configMaps)
+}
+
+func (r *ImageClusterInstallReconciler) addIndexForReferencedInputs(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, referencedSecretsIndex, func(rawObj client.Object) []string {
+		ici, ok := rawObj.(*v1alpha1.ImageClusterInstall)
+		if !ok {
+			return nil
+		}
+		secrets, _ := referencedInputs(ici)
+		return secrets
+	}); err != nil {
+		return err
+	}
+	return mgr.GetFieldIndexer().IndexField(context.TODO(), &v1alpha1.ImageClusterInstall{}, referencedConfigMapsIndex, func(rawObj client.Object) []string {
+		ici, ok := rawObj.(*v1alpha1.ImageClusterInstall)
+		if !ok {
+			return nil
+		}
+		_, configMaps := referencedInputs(ici)
+		return configMaps
+	})
+}
+
+// mapSecretToICIs requeues the ImageClusterInstalls whose installation hasn't started when a Secret their
+// configuration image is built from changes
+func (r *ImageClusterInstallReconciler) mapSecretToICIs(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.mapInputToICIs(ctx, obj, referencedSecretsIndex)
+}
+
+// mapConfigMapToICIs requeues the ImageClusterInstalls whose installation hasn't started when a ConfigMap their
+// configuration image is built from changes
+func (r *ImageClusterInstallReconciler) mapConfigMapToICIs(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.mapInputToICIs(ctx, obj, referencedConfigMapsIndex)
+}
+
+func (r *ImageClusterInstallReconciler) mapInputToICIs(ctx context.Context, obj client.Object, index string) []reconcile.Request {
+	iciList := &v1alpha1.ImageClusterInstallList{}
+	if err := r.List(ctx, iciList, client.InNamespace(obj.GetNamespace()), client.MatchingFields{index: obj.GetName()}); err != nil {
+		r.Log.WithError(err).Errorf("failed to list the ImageClusterInstalls referencing %s/%s", obj.GetNamespace(), obj.GetName())
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+	for _, ici := range iciList.Items {
+		if ici.Status.BootTime.IsZero() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: ici.Namespace, Name: ici.Name},
+			})
+		}
+	}
+	return requests
+}
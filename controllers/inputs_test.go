@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/internal/credentials"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("imageUpToDate", func() {
+	var (
+		isoWorkDir string
+		ici        *v1alpha1.ImageClusterInstall
+	)
+
+	BeforeEach(func() {
+		isoWorkDir = GinkgoT().TempDir()
+		ici = &v1alpha1.ImageClusterInstall{}
+	})
+
+	writeImage := func(inputsHash string) {
+		for _, file := range []string{
+			filepath.Join(isoWorkDir, IsoName),
+			filepath.Join(isoWorkDir, authDir, kubeAdminFile),
+			filepath.Join(isoWorkDir, authDir, credentials.Kubeconfig),
+			filepath.Join(isoWorkDir, ClusterConfigDir, credentials.SeedReconfigurationFileName),
+		} {
+			Expect(os.MkdirAll(filepath.Dir(file), 0700)).To(Succeed())
+			Expect(os.WriteFile(file, []byte("test"), 0600)).To(Succeed())
+		}
+		if inputsHash != "" {
+			Expect(os.WriteFile(filepath.Join(isoWorkDir, imageInputsFile), []byte(inputsHash), 0600)).To(Succeed())
+		}
+	}
+
+	It("is false without an image", func() {
+		Expect(imageUpToDate(ici, isoWorkDir, "hash")).To(BeFalse())
+	})
+
+	It("is true when the image was built from the same inputs", func() {
+		writeImage("hash")
+		Expect(imageUpToDate(ici, isoWorkDir, "hash")).To(BeTrue())
+	})
+
+	It("is false when the inputs changed", func() {
+		writeImage("old")
+		Expect(imageUpToDate(ici, isoWorkDir, "hash")).To(BeFalse())
+	})
+
+	It("keeps the image once the installation started", func() {
+		writeImage("old")
+		ici.Status.BareMetalHostRef = &v1alpha1.BareMetalHostReference{Name: "bmh", Namespace: "test"}
+		Expect(imageUpToDate(ici, isoWorkDir, "hash")).To(BeTrue())
+	})
+
+	It("keeps images built before the inputs were tracked", func() {
+		writeImage("")
+		Expect(imageUpToDate(ici, isoWorkDir, "hash")).To(BeTrue())
+	})
+})
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	apicfgv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+// hubImageDigestMirrors returns the image digest mirrors of the hub's ImageDigestMirrorSets, sorted by name
+func (r *ImageClusterInstallReconciler) hubImageDigestMirrors(ctx context.Context) ([]apicfgv1.ImageDigestMirrors, error) {
+	idmsList := &apicfgv1.ImageDigestMirrorSetList{}
+	if err := r.List(ctx, idmsList); err != nil {
+		return nil, fmt.Errorf("failed to list ImageDigestMirrorSets: %w", err)
+	}
+	sort.Slice(idmsList.Items, func(i, j int) bool {
+		return idmsList.Items[i].Name < idmsList.Items[j].Name
+	})
+
+	mirrors := []apicfgv1.ImageDigestMirrors{}
+	for _, idms := range idmsList.Items {
+		mirrors = append(mirrors, idms.Spec.ImageDigestMirrors...)
+	}
+	return mirrors, nil
+}
+
+// mergeImageDigestSources adds the hub sources to the ImageDigestSources of ici. The mirrors of a source that is
+// already listed are added after the ones set by the user. It returns true when the spec changed.
+func mergeImageDigestSources(ici *v1alpha1.ImageClusterInstall, hubSources []apicfgv1.ImageDigestMirrors) bool {
+	changed := false
+	for _, hubSource := range hubSources {
+		i := slices.IndexFunc(ici.Spec.ImageDigestSources, func(source apicfgv1.ImageDigestMirrors) bool {
+			return source.Source == hubSource.Source
+		})
+		if i < 0 {
+			ici.Spec.ImageDigestSources = append(ici.Spec.ImageDigestSources, *hubSource.DeepCopy())
+			changed = true
+			continue
+		}
+		source := &ici.Spec.ImageDigestSources[i]
+		for _, mirror := range hubSource.Mirrors {
+			if !slices.Contains(source.Mirrors, mirror) {
+				source.Mirrors = append(source.Mirrors, mirror)
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// validateDisconnectedMirrors checks that the release image repository of a disconnected host is a source with mirrors
+// in the ImageDigestSources, the host would fail to pull the release images otherwise
+func (r *ImageClusterInstallReconciler) validateDisconnectedMirrors(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall) error {
+
+	if !ici.Spec.Disconnected {
+		return nil
+	}
+	releaseRepository, err := r.releaseImageRepository(ctx, ici)
+	if err != nil {
+		return err
+	}
+	for _, source := range ici.Spec.ImageDigestSources {
+		if len(source.Mirrors) > 0 && repositoryInScope(releaseRepository, source.Source) {
+			log.Debugf("Release image repository %s is mirrored by source %s", releaseRepository, source.Source)
+			return nil
+		}
+	}
+	return fmt.Errorf("the release image repository %s is not a source with mirrors in imageDigestSources, it can't be pulled by a disconnected host", releaseRepository)
+}
+
+// repositoryInScope returns true when scope is the registry of the repository, the repository itself or one of its
+// parent namespaces
+func repositoryInScope(repository, scope string) bool {
+	scope = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(scope, "https://"), "http://"), "/")
+	return repository == scope || strings.HasPrefix(repository, scope+"/")
+}
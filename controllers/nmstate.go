@@ -0,0 +1,211 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	nmstateInterfaceTypeEthernet = "ethernet"
+	nmstateInterfaceStateAbsent  = "absent"
+	nmstateIdentifierMACAddress  = "mac-address"
+
+	ipv4Family = "IPv4"
+	ipv6Family = "IPv6"
+)
+
+// ipFamilies lists the IP families in the order they are validated, with their default route destinations
+var ipFamilies = []struct {
+	name         string
+	defaultRoute string
+}{
+	{name: ipv4Family, defaultRoute: "0.0.0.0/0"},
+	{name: ipv6Family, defaultRoute: "::/0"},
+}
+
+// nmstateNetworkConfig holds the parts of an nmstate network configuration used for defaulting and validation
+type nmstateNetworkConfig struct {
+	Hostname struct {
+		Config  string `json:"config,omitempty"`
+		Running string `json:"running,omitempty"`
+	} `json:"hostname,omitempty"`
+	DNSResolver struct {
+		Config struct {
+			Server []string `json:"server,omitempty"`
+		} `json:"config,omitempty"`
+	} `json:"dns-resolver,omitempty"`
+	Routes struct {
+		Config []nmstateRoute `json:"config,omitempty"`
+	} `json:"routes,omitempty"`
+	Interfaces []nmstateInterface `json:"interfaces,omitempty"`
+}
+
+type nmstateInterface struct {
+	Name       string          `json:"name"`
+	Type       string          `json:"type,omitempty"`
+	State      string          `json:"state,omitempty"`
+	Identifier string          `json:"identifier,omitempty"`
+	MACAddress string          `json:"mac-address,omitempty"`
+	IPv4       nmstateIPConfig `json:"ipv4,omitempty"`
+	IPv6       nmstateIPConfig `json:"ipv6,omitempty"`
+}
+
+type nmstateIPConfig struct {
+	DHCP     bool `json:"dhcp,omitempty"`
+	Autoconf bool `json:"autoconf,omitempty"`
+	Address  []struct {
+		IP string `json:"ip"`
+		// nmstate accepts quoted prefix lengths
+		PrefixLength intstr.IntOrString `json:"prefix-length"`
+	} `json:"address,omitempty"`
+}
+
+type nmstateRoute struct {
+	Destination      string `json:"destination"`
+	NextHopAddress   string `json:"next-hop-address,omitempty"`
+	NextHopInterface string `json:"next-hop-interface,omitempty"`
+	State            string `json:"state,omitempty"`
+}
+
+// parseNMState parses an nmstate network configuration, fields that don't have the expected types are errors
+func parseNMState(nmstate []byte) (*nmstateNetworkConfig, error) {
+	config := &nmstateNetworkConfig{}
+	if err := yaml.Unmarshal(nmstate, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal nmstate data: %w", err)
+	}
+	return config, nil
+}
+
+// validateNetworkConfig checks that the interfaces of the nmstate configuration match the host NICs, that its
+// static addresses are in the machine networks, and that the IP families with static addresses have DNS servers
+// and a default route. The NICs are only checked when nics is not nil, the addresses only when machineNetworks is
+// not empty.
+func validateNetworkConfig(config *nmstateNetworkConfig, nics []bmh_v1alpha1.NIC, machineNetworks []string) error {
+	errs := []error{}
+	staticFamilies := map[string]bool{}
+	for _, iface := range config.Interfaces {
+		if iface.State == nmstateInterfaceStateAbsent {
+			continue
+		}
+		if nics != nil {
+			if err := validateInterfaceNIC(iface, nics); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		for _, family := range ipFamilies {
+			ipConfig := iface.IPv4
+			if family.name == ipv6Family {
+				ipConfig = iface.IPv6
+			}
+			if ipConfig.DHCP || ipConfig.Autoconf {
+				continue
+			}
+			for _, address := range ipConfig.Address {
+				ip := net.ParseIP(address.IP)
+				if ip == nil {
+					errs = append(errs, fmt.Errorf("interface %s has invalid address %q", iface.Name, address.IP))
+					continue
+				}
+				if !usableIP(ip) {
+					continue
+				}
+				staticFamilies[family.name] = true
+				if len(machineNetworks) > 0 && !inAnyNetwork(address.IP, machineNetworks) {
+					errs = append(errs, fmt.Errorf("address %s of interface %s is not in any machine network %v", address.IP, iface.Name, machineNetworks))
+				}
+			}
+		}
+	}
+
+	if len(staticFamilies) > 0 && len(config.DNSResolver.Config.Server) == 0 {
+		errs = append(errs, fmt.Errorf("no DNS servers are configured for the static addresses"))
+	}
+	for _, family := range ipFamilies {
+		if staticFamilies[family.name] && !hasRoute(config.Routes.Config, family.defaultRoute) {
+			errs = append(errs, fmt.Errorf("no %s default route (%s) is configured for the static %s addresses", family.name, family.defaultRoute, family.name))
+		}
+	}
+
+	return k8serrors.NewAggregate(errs)
+}
+
+// validateInterfaceNIC checks that an ethernet interface matches a NIC by MAC address, or by name when it has none.
+// Virtual interfaces such as bonds and VLANs aren't in the inspection data.
+func validateInterfaceNIC(iface nmstateInterface, nics []bmh_v1alpha1.NIC) error {
+	if iface.Type != "" && iface.Type != nmstateInterfaceTypeEthernet {
+		return nil
+	}
+	if iface.MACAddress != "" {
+		for _, nic := range nics {
+			if strings.EqualFold(nic.MAC, iface.MACAddress) {
+				return nil
+			}
+		}
+		return fmt.Errorf("interface %s MAC address %s matches no NIC of the host", iface.Name, iface.MACAddress)
+	}
+	if iface.Identifier == nmstateIdentifierMACAddress {
+		return fmt.Errorf("interface %s is identified by MAC address but has no mac-address", iface.Name)
+	}
+	for _, nic := range nics {
+		if nic.Name == iface.Name {
+			return nil
+		}
+	}
+	return fmt.Errorf("interface %s matches no NIC of the host", iface.Name)
+}
+
+func inAnyNetwork(ip string, networks []string) bool {
+	for _, network := range networks {
+		if inCIDR, _ := ipInCidr(ip, network); inCIDR {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRoute(routes []nmstateRoute, destination string) bool {
+	for _, route := range routes {
+		if route.State != nmstateInterfaceStateAbsent && route.Destination == destination {
+			return true
+		}
+	}
+	return false
+}
+
+// validateHostNetworkConfig validates the nmstate configuration of the host against the NICs found by inspection and
+// the machine networks of the ImageClusterInstall. Hosts without inspection data only get the NIC checks skipped.
+func (r *ImageClusterInstallReconciler) validateHostNetworkConfig(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	bmh *bmh_v1alpha1.BareMetalHost) error {
+
+	config, err := r.networkConfig(ctx, ici, bmh)
+	if err != nil || config == nil {
+		return err
+	}
+
+	machineNetworks, err := getEffectiveMachineNetworksCIDRs(ici.Spec.MachineNetwork, ici.Spec.MachineNetworks, r.Log)
+	if err != nil {
+		return err
+	}
+
+	var nics []bmh_v1alpha1.NIC
+	if bmh != nil && bmh.Status.HardwareDetails != nil {
+		nics = bmh.Status.HardwareDetails.NIC
+	}
+	if err := validateNetworkConfig(config, nics, machineNetworks); err != nil {
+		return fmt.Errorf("invalid host network config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validateNetworkConfig", func() {
+	nics := []bmh_v1alpha1.NIC{
+		{Name: "enp1s0", MAC: "52:54:00:8a:88:a8", IP: "192.168.136.138"},
+		{Name: "enp2s0", MAC: "52:54:00:8a:88:a9"},
+	}
+
+	parse := func(nmstate string) *nmstateNetworkConfig {
+		config, err := parseNMState([]byte(nmstate))
+		Expect(err).NotTo(HaveOccurred())
+		return config
+	}
+
+	It("accepts a config that matches the host and machine networks", func() {
+		config := parse(validNMStateConfigBMH)
+		Expect(validateNetworkConfig(config, nics, []string{"192.168.136.0/24"})).To(Succeed())
+	})
+
+	It("skips the NIC checks without inspection data", func() {
+		config := parse(validNMStateConfigBMH)
+		Expect(validateNetworkConfig(config, nil, nil)).To(Succeed())
+	})
+
+	It("reports interfaces that match no NIC", func() {
+		config := parse(`
+interfaces:
+  - name: enp9s0
+    type: ethernet
+  - name: eth0
+    mac-address: 52:54:00:00:00:01
+  - name: bond0
+    type: bond
+  - name: enp2s0
+    type: ethernet
+`)
+		err := validateNetworkConfig(config, nics, nil)
+		Expect(err).To(MatchError(ContainSubstring("interface enp9s0 matches no NIC of the host")))
+		Expect(err).To(MatchError(ContainSubstring("interface eth0 MAC address 52:54:00:00:00:01 matches no NIC of the host")))
+		Expect(err.Error()).NotTo(ContainSubstring("bond0"))
+		Expect(err.Error()).NotTo(ContainSubstring("enp2s0"))
+	})
+
+	It("reports static addresses outside the machine networks", func() {
+		config := parse(validNMStateConfigBMH)
+		err := validateNetworkConfig(config, nics, []string{"192.0.2.0/24"})
+		Expect(err).To(MatchError(ContainSubstring("address 192.168.136.138 of interface enp1s0 is not in any machine network [192.0.2.0/24]")))
+	})
+
+	It("reports missing DNS servers and default routes for each static IP family", func() {
+		config := parse(`
+interfaces:
+  - name: enp1s0
+    ipv4:
+      enabled: true
+      address:
+        - ip: 192.168.136.138
+          prefix-length: 24
+    ipv6:
+      enabled: true
+      address:
+        - ip: 2001:db8::10
+          prefix-length: 64
+routes:
+  config:
+    - destination: 0.0.0.0/0
+      next-hop-address: 192.168.136.1
+`)
+		err := validateNetworkConfig(config, nics, nil)
+		Expect(err).To(MatchError(ContainSubstring("no DNS servers are configured")))
+		Expect(err).To(MatchError(ContainSubstring("no IPv6 default route (::/0)")))
+		Expect(err.Error()).NotTo(ContainSubstring("IPv4 default route"))
+	})
+
+	It("doesn't require DNS servers or routes with DHCP", func() {
+		config := parse(`
+interfaces:
+  - name: enp1s0
+    ipv4:
+      enabled: true
+      dhcp: true
+`)
+		Expect(validateNetworkConfig(config, nics, []string{"192.0.2.0/24"})).To(Succeed())
+	})
+
+	It("fails to parse fields with unexpected types", func() {
+		_, err := parseNMState([]byte(`
+interfaces:
+  - name: enp1s0
+    ipv4:
+      address: 192.168.136.138/24
+`))
+		Expect(err).To(HaveOccurred())
+	})
+})
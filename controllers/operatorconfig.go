@@ -0,0 +1,239 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8serrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultInstallTimeout is the install timeout when neither the ImageClusterInstall nor the operator config set one
+	DefaultInstallTimeout = time.Hour
+
+	defaultHostValidationRequeueInterval  = 30 * time.Second
+	defaultImageCreationRequeueInterval   = 5 * time.Second
+	defaultInstallProgressRequeueInterval = time.Minute
+)
+
+// operatorConfig is the spec of a valid ImageBasedInstallOperatorConfig, its accessors return the operator defaults
+// for the unset fields
+type operatorConfig struct {
+	spec v1alpha1.ImageBasedInstallOperatorConfigSpec
+}
+
+// getOperatorConfig reads the ImageBasedInstallOperatorConfig, an empty config is returned when it doesn't exist or
+// is invalid. The config is read on every reconcile so its changes apply without restarting the operator.
+func getOperatorConfig(ctx context.Context, c client.Reader, log logrus.FieldLogger) (*operatorConfig, error) {
+	config := &v1alpha1.ImageBasedInstallOperatorConfig{}
+	if err := c.Get(ctx, types.NamespacedName{Name: v1alpha1.OperatorConfigName}, config); err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			return &operatorConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to get ImageBasedInstallOperatorConfig %s: %w", v1alpha1.OperatorConfigName, err)
+	}
+	if err := validateOperatorConfig(&config.Spec); err != nil {
+		log.WithError(err).Warnf("Ignoring invalid ImageBasedInstallOperatorConfig %s", config.Name)
+		return &operatorConfig{}, nil
+	}
+	return &operatorConfig{spec: config.Spec}, nil
+}
+
+func (c *operatorConfig) installTimeout(fallback time.Duration) time.Duration {
+	if c.spec.Timeouts != nil {
+		return durationOrDefault(c.spec.Timeouts.Install, fallback)
+	}
+	return fallback
+}
+
+// phaseTimeouts returns the default timeouts of the installation phases
+func (c *operatorConfig) phaseTimeouts() v1alpha1.PhaseTimeouts {
+	if c.spec.Timeouts != nil {
+		return c.spec.Timeouts.PhaseTimeouts
+	}
+	return v1alpha1.PhaseTimeouts{}
+}
+
+func (c *operatorConfig) hostValidationInterval() time.Duration {
+	if c.spec.RequeueIntervals != nil {
+		return durationOrDefault(c.spec.RequeueIntervals.HostValidation, defaultHostValidationRequeueInterval)
+	}
+	return defaultHostValidationRequeueInterval
+}
+
+func (c *operatorConfig) imageCreationInterval() time.Duration {
+	if c.spec.RequeueIntervals != nil {
+		return durationOrDefault(c.spec.RequeueIntervals.ImageCreation, defaultImageCreationRequeueInterval)
+	}
+	return defaultImageCreationRequeueInterval
+}
+
+func (c *operatorConfig) installProgressInterval() time.Duration {
+	if c.spec.RequeueIntervals != nil {
+		return durationOrDefault(c.spec.RequeueIntervals.InstallProgress, defaultInstallProgressRequeueInterval)
+	}
+	return defaultInstallProgressRequeueInterval
+}
+
+// imageBuilds returns the number of parallel image builds, fallback when unset
+func (c *operatorConfig) imageBuilds(fallback int) int {
+	if c.spec.Concurrency != nil && c.spec.Concurrency.ImageBuilds != nil {
+		return int(*c.spec.Concurrency.ImageBuilds)
+	}
+	return fallback
+}
+
+func (c *operatorConfig) defaults() v1alpha1.InstallDefaults {
+	if c.spec.Defaults != nil {
+		return *c.spec.Defaults
+	}
+	return v1alpha1.InstallDefaults{}
+}
+
+// requirePullSecretCoverage returns whether missing pull secret credentials fail the requirements
+func (c *operatorConfig) requirePullSecretCoverage() bool {
+	return c.spec.RequirePullSecretCoverage
+}
+
+// isoRetention returns how long the configuration image of an installed cluster is kept, zero to keep it
+func (c *operatorConfig) isoRetention() time.Duration {
+	return durationOrDefault(c.spec.ISORetention, 0)
+}
+
+// imageServerURL returns the external URL of the image server, fallback when unset
+func (c *operatorConfig) imageServerURL(fallback string) string {
+	if c.spec.ImageServerURL != "" {
+		return c.spec.ImageServerURL
+	}
+	return fallback
+}
+
+// mapOperatorConfigToICIs requeues all the ImageClusterInstalls when the operator config changes, the defaults and the
+// image server URL apply to the ones whose installation hasn't started and the ISO retention to the installed ones
+func (r *ImageClusterInstallReconciler) mapOperatorConfigToICIs(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != v1alpha1.OperatorConfigName {
+		return []reconcile.Request{}
+	}
+	iciList := &v1alpha1.ImageClusterInstallList{}
+	if err := r.List(ctx, iciList); err != nil {
+		r.Log.WithError(err).Error("failed to list ImageClusterInstalls for the ImageBasedInstallOperatorConfig")
+		return []reconcile.Request{}
+	}
+
+	requests := make([]reconcile.Request, 0, len(iciList.Items))
+	for _, ici := range iciList.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: ici.Namespace, Name: ici.Name},
+		})
+	}
+	return requests
+}
+
+func durationOrDefault(d *metav1.Duration, fallback time.Duration) time.Duration {
+	if d == nil {
+		return fallback
+	}
+	return d.Duration
+}
+
+// validateOperatorConfig returns all the reasons the operator config can't be applied
+func validateOperatorConfig(spec *v1alpha1.ImageBasedInstallOperatorConfigSpec) error {
+	errs := []error{}
+	type durationField struct {
+		name  string
+		value *metav1.Duration
+	}
+	durations := []durationField{{"isoRetention", spec.ISORetention}}
+	if spec.Timeouts != nil {
+		durations = append(durations,
+			durationField{"timeouts.install", spec.Timeouts.Install},
+			durationField{"timeouts.powerOn", spec.Timeouts.PowerOn},
+			durationField{"timeouts.configImageAttached", spec.Timeouts.ConfigImageAttached},
+			durationField{"timeouts.spokeAPIReachable", spec.Timeouts.SpokeAPIReachable},
+			durationField{"timeouts.clusterConverged", spec.Timeouts.ClusterConverged})
+	}
+	if spec.RequeueIntervals != nil {
+		durations = append(durations,
+			durationField{"requeueIntervals.hostValidation", spec.RequeueIntervals.HostValidation},
+			durationField{"requeueIntervals.imageCreation", spec.RequeueIntervals.ImageCreation},
+			durationField{"requeueIntervals.installProgress", spec.RequeueIntervals.InstallProgress})
+	}
+	for _, d := range durations {
+		if d.value != nil && d.value.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive", d.name))
+		}
+	}
+	if spec.Timeouts != nil {
+		if err := v1alpha1.ValidatePhaseTimeouts(&spec.Timeouts.PhaseTimeouts); err != nil {
+			errs = append(errs, fmt.Errorf("invalid timeouts: %w", err))
+		}
+	}
+
+	if spec.Concurrency != nil && spec.Concurrency.ImageBuilds != nil && *spec.Concurrency.ImageBuilds < 1 {
+		errs = append(errs, errors.New("concurrency.imageBuilds must be at least 1"))
+	}
+
+	if spec.ImageServerURL != "" {
+		if err := validateHTTPURL(spec.ImageServerURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid imageServerURL: %w", err))
+		}
+	}
+
+	if spec.Defaults != nil {
+		for i, source := range spec.Defaults.ImageDigestSources {
+			if source.Source == "" {
+				errs = append(errs, fmt.Errorf("defaults.imageDigestSources[%d].source must be set", i))
+			}
+		}
+		if length := spec.Defaults.IPv4MachineNetworkPrefixLength; length != nil && (*length < 1 || *length > 32) {
+			errs = append(errs, errors.New("defaults.ipv4MachineNetworkPrefixLength must be between 1 and 32"))
+		}
+		if length := spec.Defaults.IPv6MachineNetworkPrefixLength; length != nil && (*length < 1 || *length > 128) {
+			errs = append(errs, errors.New("defaults.ipv6MachineNetworkPrefixLength must be between 1 and 128"))
+		}
+		if spec.Defaults.CABundle != "" {
+			if _, err := v1alpha1.ParseCABundle([]byte(spec.Defaults.CABundle)); err != nil {
+				errs = append(errs, fmt.Errorf("invalid defaults.caBundle: %w", err))
+			}
+		}
+		if proxy := spec.Defaults.Proxy; proxy != nil {
+			if proxy.HTTPProxy != "" {
+				if err := validateHTTPURL(proxy.HTTPProxy); err != nil {
+					errs = append(errs, fmt.Errorf("invalid defaults.proxy.httpProxy: %w", err))
+				}
+			}
+			if proxy.HTTPSProxy != "" {
+				if err := validateHTTPURL(proxy.HTTPSProxy); err != nil {
+					errs = append(errs, fmt.Errorf("invalid defaults.proxy.httpsProxy: %w", err))
+				}
+			}
+		}
+	}
+	return k8serrors.NewAggregate(errs)
+}
+
+func validateHTTPURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s must be an http or https URL", value)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%s has no host", value)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/internal/isobuilder"
+)
+
+// OperatorConfigReconciler reports whether the ImageBasedInstallOperatorConfig is valid and applies the settings
+// the other reconcilers don't read on every reconcile
+type OperatorConfigReconciler struct {
+	client.Client
+	Log     logrus.FieldLogger
+	Options *ImageClusterInstallReconcilerOptions
+	// ImageBuilder is resized to the configured image build concurrency
+	ImageBuilder *isobuilder.Pool
+}
+
+//+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imagebasedinstalloperatorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=extensions.hive.openshift.io,resources=imagebasedinstalloperatorconfigs/status,verbs=get;update;patch
+
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithField("name", req.Name)
+	if req.Name != v1alpha1.OperatorConfigName {
+		return ctrl.Result{}, nil
+	}
+
+	config := &v1alpha1.ImageBasedInstallOperatorConfig{}
+	if err := r.Get(ctx, req.NamespacedName, config); err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			log.Info("ImageBasedInstallOperatorConfig was removed, using the operator defaults")
+			r.applyImageBuilds(&operatorConfig{})
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	cond := metav1.Condition{
+		Type:               v1alpha1.OperatorConfigValidCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             v1alpha1.OperatorConfigValidReason,
+		Message:            "the configuration is applied",
+		ObservedGeneration: config.Generation,
+	}
+	applied := &operatorConfig{spec: config.Spec}
+	if err := validateOperatorConfig(&config.Spec); err != nil {
+		log.WithError(err).Warn("ImageBasedInstallOperatorConfig is invalid, using the operator defaults")
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = v1alpha1.OperatorConfigInvalidReason
+		cond.Message = fmt.Sprintf("the configuration is ignored: %s", err)
+		applied = &operatorConfig{}
+	}
+	r.applyImageBuilds(applied)
+
+	patch := client.MergeFrom(config.DeepCopy())
+	changed := meta.SetStatusCondition(&config.Status.Conditions, cond)
+	if !changed && config.Status.ObservedGeneration == config.Generation {
+		return ctrl.Result{}, nil
+	}
+	config.Status.ObservedGeneration = config.Generation
+	return ctrl.Result{}, r.Status().Patch(ctx, config, patch)
+}
+
+// applyImageBuilds resizes the image build pool, the operator environment sets the size when the config doesn't
+func (r *OperatorConfigReconciler) applyImageBuilds(config *operatorConfig) {
+	if r.ImageBuilder == nil {
+		return
+	}
+	fallback := 1
+	if r.Options != nil {
+		fallback = r.Options.ImageBuildConcurrency
+	}
+	r.ImageBuilder.SetWorkers(config.imageBuilds(fallback))
+}
+
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("OperatorConfigReconciler").
+		For(&v1alpha1.ImageBasedInstallOperatorConfig{}).
+		Complete(r)
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/sirupsen/logrus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+var _ = Describe("validateOperatorConfig", func() {
+	It("accepts a valid config", func() {
+		spec := &v1alpha1.ImageBasedInstallOperatorConfigSpec{
+			Timeouts:         &v1alpha1.OperatorTimeouts{Install: &metav1.Duration{Duration: 2 * time.Hour}},
+			RequeueIntervals: &v1alpha1.RequeueIntervals{InstallProgress: &metav1.Duration{Duration: 30 * time.Second}},
+			Concurrency:      &v1alpha1.Concurrency{ImageBuilds: ptr.To[int32](2)},
+			Defaults: &v1alpha1.InstallDefaults{
+				CABundle: testCACertificate("site", time.Now().Add(24*time.Hour)),
+				Proxy:    &v1alpha1.DefaultProxy{HTTPSProxy: "http://proxy.example.com:3128"},
+			},
+			ISORetention:   &metav1.Duration{Duration: 24 * time.Hour},
+			ImageServerURL: "https://images.example.com:8443",
+		}
+		Expect(validateOperatorConfig(spec)).To(Succeed())
+
+		config := &operatorConfig{spec: *spec}
+		Expect(config.installTimeout(time.Hour)).To(Equal(2 * time.Hour))
+		Expect(config.installProgressInterval()).To(Equal(30 * time.Second))
+		Expect(config.hostValidationInterval()).To(Equal(defaultHostValidationRequeueInterval))
+		Expect(config.imageBuilds(1)).To(Equal(2))
+		Expect(config.imageServerURL("https://service")).To(Equal("https://images.example.com:8443"))
+	})
+
+	It("reports every invalid field", func() {
+		spec := &v1alpha1.ImageBasedInstallOperatorConfigSpec{
+			Timeouts: &v1alpha1.OperatorTimeouts{PhaseTimeouts: v1alpha1.PhaseTimeouts{
+				PowerOn: &metav1.Duration{Duration: -time.Minute},
+			}},
+			RequeueIntervals: &v1alpha1.RequeueIntervals{ImageCreation: &metav1.Duration{Duration: -time.Second}},
+			Defaults: &v1alpha1.InstallDefaults{
+				CABundle:                       "not a certificate",
+				Proxy:                          &v1alpha1.DefaultProxy{HTTPProxy: "proxy.example.com:3128"},
+				IPv4MachineNetworkPrefixLength: ptr.To[int32](33),
+			},
+			ImageServerURL: "ftp://images.example.com",
+		}
+		err := validateOperatorConfig(spec)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("timeouts.powerOn must be positive"))
+		Expect(err.Error()).To(ContainSubstring("requeueIntervals.imageCreation must be positive"))
+		Expect(err.Error()).To(ContainSubstring("invalid imageServerURL: ftp://images.example.com must be an http or https URL"))
+		Expect(err.Error()).To(ContainSubstring("invalid defaults.caBundle"))
+		Expect(err.Error()).To(ContainSubstring("invalid defaults.proxy.httpProxy"))
+		Expect(err.Error()).To(ContainSubstring("defaults.ipv4MachineNetworkPrefixLength must be between 1 and 32"))
+	})
+
+	It("rejects phase timeouts that don't increase in phase order", func() {
+		spec := &v1alpha1.ImageBasedInstallOperatorConfigSpec{
+			Timeouts: &v1alpha1.OperatorTimeouts{PhaseTimeouts: v1alpha1.PhaseTimeouts{
+				ConfigImageAttached: &metav1.Duration{Duration: 30 * time.Minute},
+				ClusterConverged:    &metav1.Duration{Duration: 30 * time.Minute},
+			}},
+		}
+		err := validateOperatorConfig(spec)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("invalid timeouts: clusterConverged 30m0s must be longer than configImageAttached 30m0s"))
+	})
+})
+
+var _ = Describe("OperatorConfigReconciler", func() {
+	var (
+		c   client.Client
+		r   *OperatorConfigReconciler
+		ctx = context.Background()
+		key = types.NamespacedName{Name: v1alpha1.OperatorConfigName}
+	)
+
+	BeforeEach(func() {
+		c = fakeclient.NewClientBuilder().
+			WithScheme(scheme.Scheme).
+			WithStatusSubresource(&v1alpha1.ImageBasedInstallOperatorConfig{}).
+			Build()
+		r = &OperatorConfigReconciler{
+			Client:  c,
+			Log:     logrus.New(),
+			Options: &ImageClusterInstallReconcilerOptions{ImageBuildConcurrency: 1},
+		}
+	})
+
+	It("reports a valid config", func() {
+		config := &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec:       v1alpha1.ImageBasedInstallOperatorConfigSpec{ImageServerURL: "https://images.example.com"},
+		}
+		Expect(c.Create(ctx, config)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, key, config)).To(Succeed())
+		Expect(config.Status.ObservedGeneration).To(Equal(config.Generation))
+		cond := meta.FindStatusCondition(config.Status.Conditions, v1alpha1.OperatorConfigValidCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(v1alpha1.OperatorConfigValidReason))
+	})
+
+	It("reports an invalid config", func() {
+		config := &v1alpha1.ImageBasedInstallOperatorConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.OperatorConfigName},
+			Spec:       v1alpha1.ImageBasedInstallOperatorConfigSpec{ISORetention: &metav1.Duration{}},
+		}
+		Expect(c.Create(ctx, config)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.Get(ctx, key, config)).To(Succeed())
+		cond := meta.FindStatusCondition(config.Status.Conditions, v1alpha1.OperatorConfigValidCondition)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(v1alpha1.OperatorConfigInvalidReason))
+		Expect(cond.Message).To(Equal("the configuration is ignored: isoRetention must be positive"))
+	})
+})
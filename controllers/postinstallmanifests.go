@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	postInstallManifestInitialBackoff = 10 * time.Second
+	postInstallManifestMaxBackoff     = 10 * time.Minute
+)
+
+// postInstallManifestBackoff returns how long to wait before applying again an object that failed to apply attempts
+// times, the backoff doubles with each attempt
+func postInstallManifestBackoff(attempts int32) time.Duration {
+	backoff := postInstallManifestInitialBackoff
+	for i := int32(1); i < attempts && backoff < postInstallManifestMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, postInstallManifestMaxBackoff)
+}
+
+func postInstallManifestName(status v1alpha1.PostInstallManifestStatus) string {
+	if status.Namespace == "" {
+		return fmt.Sprintf("%s %s", status.Kind, status.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", status.Kind, status.Namespace, status.Name)
+}
+
+// postInstallManifestsPending returns true until all the objects of the post-install manifests were applied
+func postInstallManifestsPending(ici *v1alpha1.ImageClusterInstall) bool {
+	if len(ici.Spec.PostInstallManifestsRefs) == 0 {
+		return false
+	}
+	if len(ici.Status.PostInstallManifests) == 0 {
+		return true
+	}
+	for _, status := range ici.Status.PostInstallManifests {
+		if status.State != v1alpha1.PostInstallManifestApplied {
+			return true
+		}
+	}
+	return false
+}
+
+// postInstallManifestsMessage summarizes the application of the post-install manifests for the install conditions
+func postInstallManifestsMessage(statuses []v1alpha1.PostInstallManifestStatus) string {
+	applied := 0
+	message := ""
+	for _, status := range statuses {
+		switch status.State {
+		case v1alpha1.PostInstallManifestApplied:
+			applied++
+		case v1alpha1.PostInstallManifestFailed:
+			message = fmt.Sprintf(", %s failed to apply: %s", postInstallManifestName(status), status.Message)
+		}
+	}
+	return fmt.Sprintf("Waiting for post-install manifests: %d of %d applied%s", applied, len(statuses), message)
+}
+
+// postInstallManifests reads the objects of the post-install manifests in order: the ConfigMaps in the order they are
+// referenced, their manifests sorted by name and the documents of each manifest as they appear
+func (r *ImageClusterInstallMonitor) postInstallManifests(ctx context.Context, ici *v1alpha1.ImageClusterInstall) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	for _, ref := range ici.Spec.PostInstallManifestsRefs {
+		cm := &corev1.ConfigMap{}
+		key := types.NamespacedName{Name: ref.Name, Namespace: ici.Namespace}
+		if err := r.Get(ctx, key, cm); err != nil {
+			return nil, fmt.Errorf("failed to get post-install manifests ConfigMap %s: %w", ref.Name, err)
+		}
+
+		source := fmt.Sprintf("ConfigMap %s", cm.Name)
+		manifests := []extraManifest{}
+		for name, content := range cm.Data {
+			manifests = append(manifests, extraManifest{name: name, source: source, content: []byte(content)})
+		}
+		for name, content := range cm.BinaryData {
+			manifests = append(manifests, extraManifest{name: name, source: source, content: content})
+		}
+		for _, manifest := range sortedExtraManifests(manifests) {
+			manifestObjs, err := manifestObjects(manifest.content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode post-install manifest %s in %s: %w", manifest.name, manifest.source, err)
+			}
+			objects = append(objects, manifestObjs...)
+		}
+	}
+	return objects, nil
+}
+
+// applyPostInstallManifests applies the objects of the post-install manifests to the installed cluster in order. It
+// stops at the first object that fails so the objects it depends on are always applied first, failed objects are
+// retried with an exponential backoff. Each object is applied until it succeeds once, the results are recorded in the
+// status. It returns true when all the objects are applied, and otherwise when to try again.
+func (r *ImageClusterInstallMonitor) applyPostInstallManifests(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	spokeClient client.Client) (bool, time.Duration, error) {
+
+	objects, err := r.postInstallManifests(ctx, ici)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var statuses []v1alpha1.PostInstallManifestStatus
+	for _, obj := range objects {
+		status := v1alpha1.PostInstallManifestStatus{
+			APIVersion: obj.GetAPIVersion(),
+			Kind:       obj.GetKind(),
+			Namespace:  obj.GetNamespace(),
+			Name:       obj.GetName(),
+			State:      v1alpha1.PostInstallManifestPending,
+		}
+		for _, previous := range ici.Status.PostInstallManifests {
+			if previous.APIVersion == status.APIVersion && previous.Kind == status.Kind &&
+				previous.Namespace == status.Namespace && previous.Name == status.Name {
+				status = *previous.DeepCopy()
+				break
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	var retryAfter time.Duration
+	now := time.Now()
+	for i, obj := range objects {
+		status := &statuses[i]
+		if status.State == v1alpha1.PostInstallManifestApplied {
+			continue
+		}
+		if status.State == v1alpha1.PostInstallManifestFailed && status.LastAttemptTime != nil {
+			if retry := status.LastAttemptTime.Add(postInstallManifestBackoff(status.Attempts)); now.Before(retry) {
+				retryAfter = retry.Sub(now)
+				break
+			}
+		}
+
+		status.Attempts++
+		status.LastAttemptTime = &metav1.Time{Time: now}
+		if err := r.ApplyPostInstallManifest(ctx, spokeClient, obj); err != nil {
+			log.WithError(err).Warnf("failed to apply post-install manifest %s", postInstallManifestName(*status))
+			status.State = v1alpha1.PostInstallManifestFailed
+			status.Message = err.Error()
+			retryAfter = postInstallManifestBackoff(status.Attempts)
+			break
+		}
+		log.Infof("applied post-install manifest %s", postInstallManifestName(*status))
+		status.State = v1alpha1.PostInstallManifestApplied
+		status.Message = ""
+	}
+
+	applied := true
+	for _, status := range statuses {
+		if status.State != v1alpha1.PostInstallManifestApplied {
+			applied = false
+		}
+	}
+	if equality.Semantic.DeepEqual(statuses, ici.Status.PostInstallManifests) {
+		return applied, retryAfter, nil
+	}
+	patch := client.MergeFrom(ici.DeepCopy())
+	ici.Status.PostInstallManifests = statuses
+	if err := r.Status().Patch(ctx, ici, patch); err != nil {
+		return false, 0, fmt.Errorf("failed to update the post-install manifests status: %w", err)
+	}
+	return applied, retryAfter, nil
+}
+
+// applyRemainingPostInstallManifests applies the post-install manifests that aren't applied yet once the installation
+// completed without waiting for them. It returns when to try again.
+func (r *ImageClusterInstallMonitor) applyRemainingPostInstallManifests(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall) (time.Duration, error) {
+
+	if !postInstallManifestsPending(ici) {
+		return 0, nil
+	}
+	spokeClient, err := r.spokeClient(ctx, ici)
+	if err != nil {
+		log.WithError(err).Error("failed to create spoke client")
+		return 0, err
+	}
+	_, retryAfter, err := r.applyPostInstallManifests(ctx, log, ici, spokeClient)
+	if err != nil {
+		log.WithError(err).Error("failed to apply post-install manifests")
+	}
+	return retryAfter, err
+}
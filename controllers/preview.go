@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	previewAnnotation      = "imageclusterinstall." + v1alpha1.Group + "/preview"
+	previewAnnotationValue = "true"
+	previewConfigMapSuffix = "-preview"
+	extraManifestsHashKey  = "extra-manifests.sha256"
+	redactedValue          = "<redacted>"
+)
+
+func previewRequested(ici *v1alpha1.ImageClusterInstall) bool {
+	return ici.Annotations[previewAnnotation] == previewAnnotationValue
+}
+
+// PreviewConfigMapName returns the name of the ConfigMap holding the rendered install inputs of an ImageClusterInstall
+func PreviewConfigMapName(ici *v1alpha1.ImageClusterInstall) string {
+	return ici.Name + previewConfigMapSuffix
+}
+
+// preview renders the install inputs of the ImageClusterInstall into a ConfigMap for review.
+// The host is neither validated nor modified and no image is created, the installation starts once the
+// preview annotation is removed.
+func (r *ImageClusterInstallReconciler) preview(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	cond *hivev1.ClusterInstallCondition,
+) (ctrl.Result, error) {
+	cond.Reason = v1alpha1.ConfigurationPendingReason
+	if cd == nil {
+		cond.Message = "cannot render preview, ClusterDeployment is unset or unavailable"
+		return ctrl.Result{}, nil
+	}
+
+	// the host is only needed for its network configuration so it is optional here
+	var bmh *bmh_v1alpha1.BareMetalHost
+	if ici.Spec.BareMetalHostRef != nil && ici.Spec.BareMetalHostRef.Name != "" {
+		var err error
+		bmh, err = getBMH(ctx, r.Client, ici.Spec.BareMetalHostRef)
+		if err != nil {
+			cond.Message = fmt.Sprintf("failed to get BareMetalHost %s/%s", ici.Spec.BareMetalHostRef.Namespace, ici.Spec.BareMetalHostRef.Name)
+			log.Error(err)
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// the preview shows the defaults and the cluster metadata the installation would use without recording them,
+	// a cluster ID and infra ID that aren't set yet are generated again on every render
+	previewed := ici.DeepCopy()
+	if _, err := r.applyDefaults(ctx, log, previewed, bmh); err != nil {
+		cond.Message = fmt.Sprintf("failed to set defaults: %s", err)
+		log.Error(err)
+		return ctrl.Result{}, nil
+	}
+
+	metadata, err := r.clusterInstallMetadata(ctx, log, previewed, cd)
+	if err != nil {
+		cond.Message = "failed to get the cluster metadata"
+		log.Error(err)
+		return ctrl.Result{}, err
+	}
+	if metadata != nil {
+		previewed.Spec.ClusterMetadata = metadata
+	}
+
+	data, err := r.renderPreview(ctx, log, previewed, cd, bmh)
+	if err != nil {
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = fmt.Sprintf("failed to render preview: %s", err)
+		log.WithError(err).Error("failed to render preview")
+		return ctrl.Result{}, err
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PreviewConfigMapName(ici),
+			Namespace: ici.Namespace,
+		},
+	}
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+		cm.Data = data
+		return controllerutil.SetControllerReference(ici, cm, r.Scheme)
+	})
+	if err != nil {
+		cond.Reason = v1alpha1.ConfigurationFailedReason
+		cond.Message = fmt.Sprintf("failed to write preview ConfigMap %s/%s", cm.Namespace, cm.Name)
+		log.WithError(err).Error(cond.Message)
+		return ctrl.Result{}, err
+	}
+	if op != controllerutil.OperationResultNone {
+		log.Infof("Preview ConfigMap %s/%s %s", cm.Namespace, cm.Name, op)
+	}
+
+	cond.Reason = v1alpha1.PreviewRenderedReason
+	cond.Message = fmt.Sprintf("install inputs were rendered to ConfigMap %s, remove the %s annotation to start the installation", cm.Name, previewAnnotation)
+	return ctrl.Result{}, nil
+}
+
+// renderPreview writes the installer inputs the same way writeImage does in a temporary directory and returns
+// the redacted install-config, the image-based-config and the hashes of the extra manifests
+func (r *ImageClusterInstallReconciler) renderPreview(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	bmh *bmh_v1alpha1.BareMetalHost) (map[string]string, error) {
+
+	workDir, err := os.MkdirTemp("", "preview-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tempdir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := r.writeInstallerInputs(ctx, log, ici, cd, bmh, workDir, workDir); err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	for _, name := range []string{installConfigFilename, imageBasedConfigFilename} {
+		content, err := redactedFile(filepath.Join(workDir, name))
+		if err != nil {
+			return nil, err
+		}
+		data[name] = content
+	}
+
+	hashes, err := manifestHashes(filepath.Join(workDir, extraManifestsDir))
+	if err != nil {
+		return nil, err
+	}
+	data[extraManifestsHashKey] = hashes
+
+	return data, nil
+}
+
+// redactedFile reads a JSON installer input file and replaces the pull secret and the proxy credentials in it
+func redactedFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+	if _, ok := fields["pullSecret"]; ok {
+		fields["pullSecret"] = redactedValue
+	}
+	// proxy URLs may contain credentials, e.g. when they are read from the proxy Secret
+	if proxy, ok := fields["proxy"].(map[string]any); ok {
+		for _, name := range []string{"httpProxy", "httpsProxy"} {
+			if value, ok := proxy[name].(string); ok {
+				proxy[name] = redactedURL(value)
+			}
+		}
+	}
+
+	out, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	return string(out), nil
+}
+
+// redactedURL replaces the user info of a URL
+func redactedURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		return redactedValue
+	}
+	if u.User != nil {
+		u.User = url.User(redactedValue)
+	}
+	return u.String()
+}
+
+// manifestHashes returns one "<sha256>  <filename>" line per file in dir, os.ReadDir sorts them by filename
+func manifestHashes(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extra manifests: %w", err)
+	}
+
+	lines := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return "", fmt.Errorf("failed to read extra manifest %s: %w", entry.Name(), err)
+		}
+		lines = append(lines, manifestHash(entry.Name(), content))
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// extraManifestHashes returns the same lines as manifestHashes for the extra manifests before they are written
+func extraManifestHashes(manifests []extraManifest) string {
+	if len(manifests) == 0 {
+		return ""
+	}
+	sorted := sortedExtraManifests(slices.Clone(manifests))
+	lines := []string{}
+	for _, manifest := range sorted {
+		lines = append(lines, manifestHash(manifest.name, manifest.content))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func manifestHash(name string, content []byte) string {
+	sum := sha256.Sum256(content)
+	return fmt.Sprintf("%s  %s", hex.EncodeToString(sum[:]), name)
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const (
+	// the hub's global pull secret, merged into the ClusterDeployment pull secrets when the hub defaults enable it
+	globalPullSecretName      = "pull-secret"
+	globalPullSecretNamespace = "openshift-config"
+)
+
+// pullSecret returns the validated pull secret of the ClusterDeployment. When the hub defaults enable it the hub's
+// global pull secret is merged in, the credentials of the ClusterDeployment take precedence.
+func (r *ImageClusterInstallReconciler) pullSecret(ctx context.Context, cd *hivev1.ClusterDeployment) (string, error) {
+	psData, err := r.getValidPullSecret(ctx, cd.Spec.PullSecretRef, cd.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	defaults, err := r.getHubDefaults(ctx)
+	if err != nil {
+		return "", err
+	}
+	if !defaults.mergeGlobalPullSecret {
+		return psData, nil
+	}
+
+	globalRef := &corev1.LocalObjectReference{Name: globalPullSecretName}
+	globalData, err := r.getValidPullSecret(ctx, globalRef, globalPullSecretNamespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get the global pull secret: %w", err)
+	}
+	return mergePullSecrets(globalData, psData)
+}
+
+// mergePullSecrets returns the auths of both pull secrets, the ones of override replace the ones of base for the
+// same registry
+func mergePullSecrets(base, override string) (string, error) {
+	merged := imagePullSecret{Auths: map[string]map[string]interface{}{}}
+	for _, data := range []string{base, override} {
+		s := imagePullSecret{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &s); err != nil {
+			return "", fmt.Errorf("pull secret must be a well-formed JSON: %w", err)
+		}
+		for registry, auth := range s.Auths {
+			merged.Auths[registry] = auth
+		}
+	}
+	out, err := json.Marshal(merged)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// checkPullSecret validates the pull secret of the ClusterDeployment and checks that it has credentials for the
+// release image repository and for every image mirror. Missing credentials are returned as a warning, they only fail
+// the requirements when the operator config sets requirePullSecretCoverage.
+func (r *ImageClusterInstallReconciler) checkPullSecret(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment,
+	cond *hivev1.ClusterInstallCondition) (string, error) {
+
+	warning, err := r.validatePullSecretCoverage(ctx, ici, cd)
+	if err == nil && warning != "" {
+		config, configErr := getOperatorConfig(ctx, r.Client, log)
+		if configErr != nil {
+			err = configErr
+		} else if config.requirePullSecretCoverage() {
+			err = errors.New(warning)
+		}
+	}
+	if err != nil {
+		cond.Reason = v1alpha1.PullSecretInvalidReason
+		cond.Message = err.Error()
+		log.Error(err)
+		return "", err
+	}
+	if warning != "" {
+		log.Warn(warning)
+	}
+	return warning, nil
+}
+
+// validatePullSecretCoverage returns an error when the pull secret is invalid, and the missing credentials
+func (r *ImageClusterInstallReconciler) validatePullSecretCoverage(
+	ctx context.Context,
+	ici *v1alpha1.ImageClusterInstall,
+	cd *hivev1.ClusterDeployment) (string, error) {
+
+	psData, err := r.pullSecret(ctx, cd)
+	if err != nil {
+		return "", err
+	}
+	s := imagePullSecret{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(psData)), &s); err != nil {
+		return "", fmt.Errorf("pull secret must be a well-formed JSON: %w", err)
+	}
+
+	releaseRepository, err := r.releaseImageRepository(ctx, ici)
+	if err != nil {
+		return "", err
+	}
+
+	missing := []string{}
+	if !pullSecretHasCredentials(s.Auths, releaseRepository) {
+		missing = append(missing, fmt.Sprintf("release image repository %s", releaseRepository))
+	}
+	for _, source := range ici.Spec.ImageDigestSources {
+		for _, mirror := range source.Mirrors {
+			if !pullSecretHasCredentials(s.Auths, string(mirror)) {
+				missing = append(missing, fmt.Sprintf("mirror %s of %s", mirror, source.Source))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("pull secret %s has no credentials for the %s", cd.Spec.PullSecretRef.Name, strings.Join(missing, ", the ")), nil
+	}
+	return "", nil
+}
+
+// pullSecretHasCredentials returns true when one of the auths is for the registry of the repository, or for the
+// repository or one of its parent namespaces
+func pullSecretHasCredentials(auths map[string]map[string]interface{}, repository string) bool {
+	for registry := range auths {
+		if repositoryInScope(repository, registry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("mergePullSecrets", func() {
+	It("prefers the credentials of the override", func() {
+		merged, err := mergePullSecrets(
+			`{"auths":{"quay.io":{"auth":"Z2xvYmFsOnF1YXk="},"mirror.example.com":{"auth":"Z2xvYmFsOm1pcnJvcg=="}}}`,
+			`{"auths":{"quay.io":{"auth":"c2l0ZTpxdWF5"}}}`)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := imagePullSecret{}
+		Expect(json.Unmarshal([]byte(merged), &s)).To(Succeed())
+		Expect(s.Auths).To(HaveLen(2))
+		Expect(s.Auths["quay.io"]).To(HaveKeyWithValue("auth", "c2l0ZTpxdWF5"))
+		Expect(s.Auths["mirror.example.com"]).To(HaveKeyWithValue("auth", "Z2xvYmFsOm1pcnJvcg=="))
+	})
+
+	It("fails on malformed pull secrets", func() {
+		_, err := mergePullSecrets("garbage", `{"auths":{}}`)
+		Expect(err).To(MatchError(ContainSubstring("pull secret must be a well-formed JSON")))
+	})
+})
+
+var _ = Describe("pullSecretHasCredentials", func() {
+	auths := map[string]map[string]interface{}{
+		"registry.example.com":               {},
+		"https://mirror.example.com:5000/":   {},
+		"quay.io/openshift-release-dev/ocp4": {},
+	}
+
+	It("matches the registry or a parent namespace of the repository", func() {
+		Expect(pullSecretHasCredentials(auths, "registry.example.com/ocp/release")).To(BeTrue())
+		Expect(pullSecretHasCredentials(auths, "mirror.example.com:5000/ocp")).To(BeTrue())
+		Expect(pullSecretHasCredentials(auths, "quay.io/openshift-release-dev/ocp4/release")).To(BeTrue())
+	})
+
+	It("doesn't match other registries and namespaces", func() {
+		Expect(pullSecretHasCredentials(auths, "quay.io/openshift-release-dev/ocp-release")).To(BeFalse())
+		Expect(pullSecretHasCredentials(auths, "registry.example.community/ocp")).To(BeFalse())
+		Expect(pullSecretHasCredentials(auths, "mirror.example.com/ocp")).To(BeFalse())
+	})
+})
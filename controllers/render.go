@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	bmh_v1alpha1 "github.com/metal3-io/baremetal-operator/apis/metal3.io/v1alpha1"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+	"github.com/openshift/image-based-install-operator/internal/credentials"
+)
+
+// RenderImage defaults and validates an ImageClusterInstall and the objects it references the same way the webhook
+// and Reconcile do, and writes its configuration iso and auth files to workDir.
+// Referenced objects are read from r.Client, the host is not modified and no credential secrets are created.
+// It is used to render configuration images without a hub.
+func (r *ImageClusterInstallReconciler) RenderImage(
+	ctx context.Context,
+	log logrus.FieldLogger,
+	ici *v1alpha1.ImageClusterInstall,
+	workDir string) error {
+
+	if err := v1alpha1.DefaultImageClusterInstall(ici); err != nil {
+		return fmt.Errorf("invalid ImageClusterInstall: %w", err)
+	}
+	warnings, err := v1alpha1.ValidateImageClusterInstall(ctx, r.Client, ici, r.Options.StrictAdmissionValidation)
+	for _, warning := range warnings {
+		log.Warn(warning)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid ImageClusterInstall: %w", err)
+	}
+
+	if ici.Spec.ClusterDeploymentRef == nil || ici.Spec.ClusterDeploymentRef.Name == "" {
+		return errors.New("ClusterDeploymentRef is unset")
+	}
+	cd, err := r.getCD(ctx, ici)
+	if err != nil {
+		return fmt.Errorf("failed to get ClusterDeployment %s/%s: %w", ici.Namespace, ici.Spec.ClusterDeploymentRef.Name, err)
+	}
+
+	var bmh *bmh_v1alpha1.BareMetalHost
+	if ici.Spec.BareMetalHostRef != nil && ici.Spec.BareMetalHostRef.Name != "" {
+		bmh, err = getBMH(ctx, r.Client, ici.Spec.BareMetalHostRef)
+		if err != nil {
+			return fmt.Errorf("failed to get BareMetalHost %s/%s: %w", ici.Spec.BareMetalHostRef.Namespace, ici.Spec.BareMetalHostRef.Name, err)
+		}
+
+		cond := hivev1.ClusterInstallCondition{}
+		res, err := r.validateBMH(ici, bmh, &cond)
+		if err != nil {
+			return fmt.Errorf("invalid BareMetalHost %s/%s: %w", bmh.Namespace, bmh.Name, err)
+		}
+		if !res.IsZero() {
+			// a host read from a manifest usually has no status to validate against
+			log.Warnf("Skipping hardware validation: %s", cond.Message)
+		}
+	}
+
+	// defaults are only applied to the rendered image, the manifests are not updated
+	defaulted, err := r.applyDefaults(ctx, log, ici, bmh)
+	if err != nil {
+		return fmt.Errorf("failed to set defaults: %w", err)
+	}
+	if len(defaulted) > 0 {
+		log.Infof("Defaulted %s, set them in the ImageClusterInstall to keep them for a reinstall", strings.Join(defaulted, ", "))
+	}
+
+	cond := hivev1.ClusterInstallCondition{}
+	if _, _, err := r.validateImageInputs(ctx, log, ici, cd, bmh, &cond); err != nil {
+		return fmt.Errorf("%s: %w", cond.Reason, err)
+	}
+
+	// like the defaults, the cluster metadata is only set on the rendered image
+	metadata, err := r.clusterInstallMetadata(ctx, log, ici, cd)
+	if err != nil {
+		return fmt.Errorf("failed to set cluster metadata: %w", err)
+	}
+	if metadata != nil {
+		ici.Spec.ClusterMetadata = metadata
+	}
+	log.Infof("Using cluster ID %s and infra ID %s", ici.Spec.ClusterMetadata.ClusterID, ici.Spec.ClusterMetadata.InfraID)
+
+	return r.writeImage(ctx, log, ici, cd, bmh, workDir)
+}
+
+// RenderedFiles returns the paths of the configuration iso and the auth files written by RenderImage to workDir
+func RenderedFiles(workDir string) []string {
+	return []string{
+		filepath.Join(workDir, IsoName),
+		filepath.Join(workDir, authDir, credentials.Kubeconfig),
+		filepath.Join(workDir, authDir, kubeAdminFile),
+	}
+}
@@ -1,16 +1,49 @@
 package filelock
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/sirupsen/logrus"
 )
 
-const lockFileName = "lock"
+const (
+	lockFileName = "lock"
+
+	defaultRetryDelay = 500 * time.Millisecond
+)
+
+// Owner identifies the holder of a write lock, it is written into the lock file while the lock is held
+type Owner struct {
+	Pod         string    `json:"pod,omitempty"`
+	ReconcileID string    `json:"reconcileID,omitempty"`
+	Since       time.Time `json:"since"`
+}
+
+// LockOptions configures WithWriteLockContext and WithReadLockContext
+type LockOptions struct {
+	// Owner is recorded in the lock file while a write lock is held, Since is set when the lock is acquired
+	Owner Owner
+	// Timeout is the maximum time to wait for the lock, zero means a single attempt
+	Timeout time.Duration
+	// RetryDelay is the interval between attempts to acquire the lock
+	RetryDelay time.Duration
+	// StaleThreshold is the time after which a lock held by someone else is reported as stale, zero disables the check
+	StaleThreshold time.Duration
+	Log            logrus.FieldLogger
+}
+
+func lockPath(dir string) string {
+	return filepath.Join(dir, lockFileName)
+}
 
 func lockForDir(dir string) (*flock.Flock, error) {
-	p := filepath.Join(dir, lockFileName)
+	p := lockPath(dir)
 	_, err := os.Stat(p)
 	if os.IsNotExist(err) {
 		if err := os.WriteFile(p, []byte{}, 0600); err != nil { //nolint:govet // shadow: err in if scope
@@ -62,3 +95,157 @@ func WithReadLock(dir string, f func() error) (bool, error, error) {
 
 	return true, nil, f()
 }
+
+// WithWriteLockContext runs the given function while holding a write lock on the directory `dir`
+// Unlike WithWriteLock it waits up to opts.Timeout for the lock to become available and records
+// opts.Owner in the lock file while the lock is held.
+// The return values have the same meaning as for WithWriteLock, failing to acquire the lock before
+// the timeout is not considered an error.
+func WithWriteLockContext(ctx context.Context, dir string, opts LockOptions, f func() error) (bool, error, error) {
+	lock, err := lockForDir(dir)
+	if err != nil {
+		return false, err, nil
+	}
+	locked, err := waitForLock(ctx, dir, lock.TryLock, opts)
+	if err != nil || !locked {
+		return false, err, nil
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	owner := opts.Owner
+	owner.Since = time.Now()
+	if err := writeOwner(dir, owner); err != nil {
+		return true, err, nil
+	}
+	defer clearOwner(dir, opts.Log)
+	defer reportHeldTime(dir, owner, opts)
+
+	return true, nil, f()
+}
+
+// WithReadLockContext runs the given function while holding a read lock on the directory `dir`
+// It waits up to opts.Timeout for the lock to become available, see WithWriteLockContext.
+// Read locks are shared so the owner is not recorded in the lock file.
+func WithReadLockContext(ctx context.Context, dir string, opts LockOptions, f func() error) (bool, error, error) {
+	lock, err := lockForDir(dir)
+	if err != nil {
+		return false, err, nil
+	}
+	locked, err := waitForLock(ctx, dir, lock.TryRLock, opts)
+	if err != nil || !locked {
+		return false, err, nil
+	}
+	defer lock.Unlock() //nolint:errcheck
+
+	return true, nil, f()
+}
+
+// ReadOwner returns the owner recorded in the lock file for `dir`
+// It returns nil if no write lock holder is recorded
+func ReadOwner(dir string) (*Owner, error) {
+	data, err := os.ReadFile(lockPath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	owner := &Owner{}
+	if err := json.Unmarshal(data, owner); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// waitForLock calls tryLock until it succeeds, fails, or opts.Timeout expires
+// While waiting, a holder that has had the lock for longer than opts.StaleThreshold is logged and
+// exported as a metric. The time spent waiting is exported as a metric as well.
+func waitForLock(ctx context.Context, dir string, tryLock func() (bool, error), opts LockOptions) (bool, error) {
+	retryDelay := opts.RetryDelay
+	if retryDelay <= 0 {
+		retryDelay = defaultRetryDelay
+	}
+	start := time.Now()
+	deadline := start.Add(opts.Timeout)
+	reportedStale := false
+	defer func() { lockWaitSeconds.Observe(time.Since(start).Seconds()) }()
+
+	for {
+		locked, err := tryLock()
+		if locked || err != nil {
+			return locked, err
+		}
+
+		if !reportedStale {
+			reportedStale = checkStaleOwner(dir, opts)
+		}
+
+		if !time.Now().Add(retryDelay).Before(deadline) {
+			return false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// checkStaleOwner reports the current lock holder if it held the lock for longer than opts.StaleThreshold
+func checkStaleOwner(dir string, opts LockOptions) bool {
+	if opts.StaleThreshold <= 0 {
+		return false
+	}
+	owner, err := ReadOwner(dir)
+	if err != nil {
+		if opts.Log != nil {
+			opts.Log.WithError(err).Warnf("failed to read lock owner for %s", dir)
+		}
+		return false
+	}
+	if owner == nil || owner.Since.IsZero() {
+		return false
+	}
+
+	held := time.Since(owner.Since)
+	if held < opts.StaleThreshold {
+		return false
+	}
+	staleLocksTotal.Inc()
+	staleLockHeldSeconds.Observe(held.Seconds())
+	if opts.Log != nil {
+		opts.Log.Warnf("lock on %s has been held for %s by pod %q (reconcile %q) since %s",
+			dir, held.Round(time.Second), owner.Pod, owner.ReconcileID, owner.Since.Format(time.RFC3339))
+	}
+	return true
+}
+
+func reportHeldTime(dir string, owner Owner, opts LockOptions) {
+	held := time.Since(owner.Since)
+	if opts.StaleThreshold <= 0 || held < opts.StaleThreshold {
+		return
+	}
+	staleLocksTotal.Inc()
+	if opts.Log != nil {
+		opts.Log.Warnf("lock on %s was held for %s by reconcile %q", dir, held.Round(time.Second), owner.ReconcileID)
+	}
+}
+
+func writeOwner(dir string, owner Owner) error {
+	data, err := json.Marshal(owner)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(lockPath(dir), data, 0600)
+}
+
+func clearOwner(dir string, log logrus.FieldLogger) {
+	// the directory may have been removed by the function run under the lock
+	if err := os.Truncate(lockPath(dir), 0); err != nil && !errors.Is(err, os.ErrNotExist) && log != nil {
+		log.WithError(err).Warnf("failed to clear lock owner for %s", dir)
+	}
+}
@@ -1,12 +1,15 @@
 package filelock
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
 )
 
 var _ = Describe("WithWriteLock", func() {
@@ -161,6 +164,113 @@ var _ = Describe("WithReadLock", func() {
 	})
 })
 
+var _ = Describe("WithWriteLockContext", func() {
+	var (
+		dir string
+		ctx context.Context
+	)
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "write_lock_context_test_data")
+		Expect(err).NotTo(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("records the owner while the lock is held", func() {
+		opts := LockOptions{Owner: Owner{Pod: "pod", ReconcileID: "id"}}
+		locked, lerr, ferr := WithWriteLockContext(ctx, dir, opts, func() error {
+			owner, err := ReadOwner(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(owner).NotTo(BeNil())
+			Expect(owner.Pod).To(Equal("pod"))
+			Expect(owner.ReconcileID).To(Equal("id"))
+			Expect(owner.Since).NotTo(BeZero())
+			return nil
+		})
+		Expect(locked).To(BeTrue())
+		Expect(lerr).NotTo(HaveOccurred())
+		Expect(ferr).NotTo(HaveOccurred())
+
+		owner, err := ReadOwner(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(owner).To(BeNil())
+	})
+
+	It("waits for the lock to be released", func() {
+		held := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			_, _, _ = WithWriteLock(dir, func() error {
+				close(held)
+				<-release
+				return nil
+			})
+		}()
+		<-held
+		time.AfterFunc(200*time.Millisecond, func() { close(release) })
+
+		opts := LockOptions{Timeout: 5 * time.Second, RetryDelay: 50 * time.Millisecond}
+		locked, lerr, ferr := WithWriteLockContext(ctx, dir, opts, func() error { return nil })
+		Expect(locked).To(BeTrue())
+		Expect(lerr).NotTo(HaveOccurred())
+		Expect(ferr).NotTo(HaveOccurred())
+	})
+
+	It("gives up after the timeout and reports a stale owner", func() {
+		c := make(chan int)
+		holder := LockOptions{Owner: Owner{Pod: "other"}}
+
+		l1, lerr, ferr := WithWriteLockContext(ctx, dir, holder, func() error {
+			// pretend the lock has been held for an hour
+			Expect(writeOwner(dir, Owner{Pod: "other", Since: time.Now().Add(-time.Hour)})).To(Succeed())
+			go func() {
+				defer func() { c <- 1 }()
+				opts := LockOptions{Timeout: 100 * time.Millisecond, RetryDelay: 10 * time.Millisecond, StaleThreshold: time.Minute}
+				l2, lerr2, ferr2 := WithReadLockContext(ctx, dir, opts, func() error { return nil })
+				Expect(l2).To(BeFalse())
+				Expect(lerr2).ToNot(HaveOccurred())
+				Expect(ferr2).ToNot(HaveOccurred())
+			}()
+			<-c
+			return nil
+		})
+		Expect(l1).To(BeTrue())
+		Expect(lerr).NotTo(HaveOccurred())
+		Expect(ferr).NotTo(HaveOccurred())
+		m := &dto.Metric{}
+		Expect(staleLocksTotal.Write(m)).To(Succeed())
+		Expect(m.GetCounter().GetValue()).To(BeNumerically(">=", 1))
+		Expect(staleLockHeldSeconds.Write(m)).To(Succeed())
+		Expect(m.GetHistogram().GetSampleCount()).To(BeNumerically(">=", 1))
+		Expect(m.GetHistogram().GetSampleSum()).To(BeNumerically(">=", time.Hour.Seconds()))
+		Expect(lockWaitSeconds.Write(m)).To(Succeed())
+		Expect(m.GetHistogram().GetSampleCount()).To(BeNumerically(">=", 1))
+	})
+
+	It("returns an error when the context is cancelled", func() {
+		c := make(chan int)
+		l1, _, _ := WithWriteLock(dir, func() error {
+			go func() {
+				defer func() { c <- 1 }()
+				cancelCtx, cancel := context.WithCancel(ctx)
+				cancel()
+				opts := LockOptions{Timeout: time.Minute, RetryDelay: 10 * time.Millisecond}
+				l2, lerr2, _ := WithWriteLockContext(cancelCtx, dir, opts, func() error { return nil })
+				Expect(l2).To(BeFalse())
+				Expect(lerr2).To(MatchError(context.Canceled))
+			}()
+			<-c
+			return nil
+		})
+		Expect(l1).To(BeTrue())
+	})
+})
+
 func TestFileLock(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Filelock Suite")
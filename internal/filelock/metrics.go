@@ -0,0 +1,27 @@
+package filelock
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	staleLocksTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "ibio_filelock_stale_total",
+		Help: "Number of times a file lock was found to be held for longer than the stale threshold",
+	})
+	lockWaitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ibio_filelock_wait_seconds",
+		Help:    "Time spent waiting for a file lock, whether it was acquired or not",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+	})
+	staleLockHeldSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ibio_filelock_stale_held_seconds",
+		Help:    "Time a stale file lock had been held for when a reconcile waiting on it found it",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 10),
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(staleLocksTotal, lockWaitSeconds, staleLockHeldSeconds)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-openapi/swag"
 	apicfgv1 "github.com/openshift/api/config/v1"
@@ -79,17 +80,26 @@ func WriteImageBaseConfig(ctx context.Context,
 	return nil
 }
 
+// SecretValues are the values of ImageClusterInstall fields that are read from referenced Secrets
+type SecretValues struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	SSHKeys    []string
+}
+
 func WriteInstallConfig(
 	ici *v1alpha1.ImageClusterInstall,
 	cd *hivev1.ClusterDeployment,
-	psData, caBundle, file string) error {
+	psData, caBundle string,
+	secretValues SecretValues,
+	file string) error {
 
 	installConfig := installertypes.InstallConfig{
 		TypeMeta:   metav1.TypeMeta{APIVersion: installertypes.InstallConfigVersion},
 		BaseDomain: cd.Spec.BaseDomain,
 		ObjectMeta: metav1.ObjectMeta{Name: cd.Spec.ClusterName},
 		Networking: &installertypes.Networking{NetworkType: "OVNKubernetes"},
-		SSHKey:     ici.Spec.SSHKey,
+		SSHKey:     sshKeys(ici.Spec.SSHKey, secretValues.SSHKeys),
 		ControlPlane: &installertypes.MachinePool{
 			Replicas: swag.Int64(1),
 			Name:     "master",
@@ -99,7 +109,7 @@ func WriteInstallConfig(
 			Name:     "worker",
 		}},
 		PullSecret: psData,
-		Proxy:      proxy(ici.Spec.Proxy),
+		Proxy:      proxy(ici.Spec.Proxy, secretValues),
 		Platform:   installertypes.Platform{None: &none.Platform{}},
 	}
 	if caBundle != "" {
@@ -154,18 +164,39 @@ func getEffectiveMachineNetworks(ici *v1alpha1.ImageClusterInstall) []string {
 	return nil
 }
 
-// all the logic of creating right noProxy is part of LCA, here we just pass it as is
-func proxy(iciProxy *v1alpha1.Proxy) *installertypes.Proxy {
-	if iciProxy == nil || (iciProxy.HTTPSProxy == "" && iciProxy.HTTPProxy == "") {
+// all the logic of creating right noProxy is part of LCA, here we just pass it as is.
+// The proxy URLs read from the proxy Secret take precedence over the ones in the spec.
+func proxy(iciProxy *v1alpha1.Proxy, secretValues SecretValues) *installertypes.Proxy {
+	if iciProxy == nil {
+		return nil
+	}
+	httpProxy, httpsProxy := iciProxy.HTTPProxy, iciProxy.HTTPSProxy
+	if secretValues.HTTPProxy != "" {
+		httpProxy = secretValues.HTTPProxy
+	}
+	if secretValues.HTTPSProxy != "" {
+		httpsProxy = secretValues.HTTPSProxy
+	}
+	if httpsProxy == "" && httpProxy == "" {
 		return nil
 	}
 	return &installertypes.Proxy{
-		HTTPProxy:  iciProxy.HTTPProxy,
-		HTTPSProxy: iciProxy.HTTPSProxy,
+		HTTPProxy:  httpProxy,
+		HTTPSProxy: httpsProxy,
 		NoProxy:    iciProxy.NoProxy,
 	}
 }
 
+// sshKeys returns the authorized keys of the spec followed by the ones read from the SSH keys Secret, one per line
+func sshKeys(specKeys string, secretKeys []string) string {
+	keys := []string{}
+	if specKeys != "" {
+		keys = append(keys, specKeys)
+	}
+	keys = append(keys, secretKeys...)
+	return strings.Join(keys, "\n")
+}
+
 func ConvertIDMToIDS(imageDigestMirrors []apicfgv1.ImageDigestMirrors) []installertypes.ImageDigestSource {
 	imageDigestSources := make([]installertypes.ImageDigestSource, len(imageDigestMirrors))
 	for i, idm := range imageDigestMirrors {
@@ -0,0 +1,242 @@
+package isobuilder
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type State string
+
+const (
+	StateQueued    State = "Queued"
+	StateRunning   State = "Running"
+	StateSucceeded State = "Succeeded"
+	StateFailed    State = "Failed"
+)
+
+// ErrQueueFull is returned by Submit when the queue already holds the maximum number of builds
+var ErrQueueFull = errors.New("image build queue is full")
+
+// BuildFunc builds the image for a single ImageClusterInstall
+// The context is cancelled when the pool is stopped
+type BuildFunc func(ctx context.Context) error
+
+// Status describes the progress of a submitted build
+type Status struct {
+	State State
+	// Err is the error returned by the build, only set in StateFailed
+	Err error
+	// Position is the number of builds queued ahead of this one, only set in StateQueued
+	Position  int
+	QueuedAt  time.Time
+	StartedAt time.Time
+}
+
+type job struct {
+	key    string
+	build  BuildFunc
+	status Status
+}
+
+// Pool runs image builds on a bounded number of workers, separately from the reconcile loop
+// Builds are identified by a key (the ImageClusterInstall UID) and their result is kept until Forget is called
+type Pool struct {
+	workers   int
+	queueSize int
+	log       logrus.FieldLogger
+
+	mu    sync.Mutex
+	cond  *sync.Cond
+	queue []*job
+	jobs  map[string]*job
+	done  bool
+
+	// ctx and wg are set by Start, running is the number of worker goroutines
+	ctx     context.Context
+	wg      sync.WaitGroup
+	running int
+}
+
+func NewPool(workers, queueSize int, log logrus.FieldLogger) *Pool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &Pool{
+		workers:   workers,
+		queueSize: queueSize,
+		log:       log,
+		jobs:      make(map[string]*job),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// Start runs the workers until ctx is done, it implements manager.Runnable
+func (p *Pool) Start(ctx context.Context) error {
+	p.mu.Lock()
+	p.log.Infof("Starting image build pool with %d workers and queue size %d", p.workers, p.queueSize)
+	p.ctx = ctx
+	p.startWorkers()
+	p.mu.Unlock()
+
+	<-ctx.Done()
+	p.mu.Lock()
+	p.done = true
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	p.wg.Wait()
+	return nil
+}
+
+// SetWorkers changes the number of workers of a started or stopped pool
+// Extra workers stop once their running build is done
+func (p *Pool) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if workers == p.workers {
+		return
+	}
+	p.log.Infof("Changing the image build workers from %d to %d", p.workers, workers)
+	p.workers = workers
+	if p.ctx != nil && !p.done {
+		p.startWorkers()
+	}
+	p.cond.Broadcast()
+}
+
+// startWorkers starts workers until there are as many as configured, it must be called with the lock held
+func (p *Pool) startWorkers() {
+	for p.running < p.workers {
+		p.running++
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(p.ctx)
+		}()
+	}
+}
+
+// Submit queues a build for key
+// It does nothing if a build for key is already known to the pool
+func (p *Pool) Submit(key string, build BuildFunc) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.jobs[key]; ok {
+		return nil
+	}
+	if p.queueSize > 0 && len(p.queue) >= p.queueSize {
+		return ErrQueueFull
+	}
+
+	j := &job{
+		key:   key,
+		build: build,
+		status: Status{
+			State:    StateQueued,
+			QueuedAt: time.Now(),
+		},
+	}
+	p.jobs[key] = j
+	p.queue = append(p.queue, j)
+	queueDepth.Set(float64(len(p.queue)))
+	p.cond.Signal()
+	return nil
+}
+
+// Status returns the status of the build for key and whether the pool knows about it
+func (p *Pool) Status(key string) (Status, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.jobs[key]
+	if !ok {
+		return Status{}, false
+	}
+	status := j.status
+	if status.State == StateQueued {
+		for i, queued := range p.queue {
+			if queued == j {
+				status.Position = i
+				break
+			}
+		}
+	}
+	return status, true
+}
+
+// Forget removes the build for key from the pool
+// A queued build is dropped, the result of a running build is discarded
+func (p *Pool) Forget(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	j, ok := p.jobs[key]
+	if !ok {
+		return
+	}
+	delete(p.jobs, key)
+	for i, queued := range p.queue {
+		if queued == j {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			queueDepth.Set(float64(len(p.queue)))
+			break
+		}
+	}
+}
+
+func (p *Pool) next() *job {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.queue) == 0 && !p.done && p.running <= p.workers {
+		p.cond.Wait()
+	}
+	if p.done {
+		return nil
+	}
+	if p.running > p.workers {
+		p.running--
+		return nil
+	}
+
+	j := p.queue[0]
+	p.queue = p.queue[1:]
+	queueDepth.Set(float64(len(p.queue)))
+	j.status.State = StateRunning
+	j.status.StartedAt = time.Now()
+	queueWaitSeconds.Observe(j.status.StartedAt.Sub(j.status.QueuedAt).Seconds())
+	return j
+}
+
+func (p *Pool) work(ctx context.Context) {
+	for {
+		j := p.next()
+		if j == nil {
+			return
+		}
+
+		p.log.Infof("Starting image build %s", j.key)
+		err := j.build(ctx)
+		buildDurationSeconds.Observe(time.Since(j.status.StartedAt).Seconds())
+
+		p.mu.Lock()
+		if err != nil {
+			p.log.WithError(err).Errorf("Image build %s failed", j.key)
+			j.status.State = StateFailed
+			j.status.Err = err
+		} else {
+			p.log.Infof("Image build %s succeeded", j.key)
+			j.status.State = StateSucceeded
+		}
+		p.mu.Unlock()
+	}
+}
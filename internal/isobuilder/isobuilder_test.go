@@ -0,0 +1,155 @@
+package isobuilder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Pool", func() {
+	var (
+		pool   *Pool
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+
+	startPool := func() {
+		go func() {
+			defer GinkgoRecover()
+			Expect(pool.Start(ctx)).To(Succeed())
+		}()
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+		pool = NewPool(1, 2, logrus.New())
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	It("runs a submitted build and records success", func() {
+		startPool()
+		Expect(pool.Submit("a", func(context.Context) error { return nil })).To(Succeed())
+		Eventually(func() State {
+			status, _ := pool.Status("a")
+			return status.State
+		}).Should(Equal(StateSucceeded))
+	})
+
+	It("records the build error", func() {
+		startPool()
+		buildErr := errors.New("boom")
+		Expect(pool.Submit("a", func(context.Context) error { return buildErr })).To(Succeed())
+		Eventually(func() State {
+			status, _ := pool.Status("a")
+			return status.State
+		}).Should(Equal(StateFailed))
+		status, found := pool.Status("a")
+		Expect(found).To(BeTrue())
+		Expect(status.Err).To(Equal(buildErr))
+	})
+
+	It("reports the queue position and rejects builds when full", func() {
+		Expect(pool.Submit("a", func(context.Context) error { return nil })).To(Succeed())
+		Expect(pool.Submit("b", func(context.Context) error { return nil })).To(Succeed())
+		Expect(pool.Submit("c", func(context.Context) error { return nil })).To(MatchError(ErrQueueFull))
+
+		status, found := pool.Status("b")
+		Expect(found).To(BeTrue())
+		Expect(status.State).To(Equal(StateQueued))
+		Expect(status.Position).To(Equal(1))
+	})
+
+	It("ignores a second submission for the same key", func() {
+		calls := 0
+		build := func(context.Context) error {
+			calls++
+			return nil
+		}
+		Expect(pool.Submit("a", build)).To(Succeed())
+		Expect(pool.Submit("a", build)).To(Succeed())
+		startPool()
+		Eventually(func() State {
+			status, _ := pool.Status("a")
+			return status.State
+		}).Should(Equal(StateSucceeded))
+		Expect(calls).To(Equal(1))
+	})
+
+	It("limits the number of concurrent builds", func() {
+		release := make(chan struct{})
+		Expect(pool.Submit("a", func(context.Context) error {
+			<-release
+			return nil
+		})).To(Succeed())
+		Expect(pool.Submit("b", func(context.Context) error { return nil })).To(Succeed())
+		startPool()
+
+		Eventually(func() State {
+			status, _ := pool.Status("a")
+			return status.State
+		}).Should(Equal(StateRunning))
+		Consistently(func() State {
+			status, _ := pool.Status("b")
+			return status.State
+		}, 200*time.Millisecond).Should(Equal(StateQueued))
+
+		close(release)
+		Eventually(func() State {
+			status, _ := pool.Status("b")
+			return status.State
+		}).Should(Equal(StateSucceeded))
+	})
+
+	It("runs more builds concurrently when the workers are increased", func() {
+		release := make(chan struct{})
+		defer close(release)
+		for _, key := range []string{"a", "b"} {
+			Expect(pool.Submit(key, func(context.Context) error {
+				<-release
+				return nil
+			})).To(Succeed())
+		}
+		startPool()
+		Eventually(func() State {
+			status, _ := pool.Status("a")
+			return status.State
+		}).Should(Equal(StateRunning))
+
+		pool.SetWorkers(2)
+		Eventually(func() State {
+			status, _ := pool.Status("b")
+			return status.State
+		}).Should(Equal(StateRunning))
+	})
+
+	It("stops extra workers when the workers are decreased", func() {
+		pool.SetWorkers(2)
+		startPool()
+		pool.SetWorkers(1)
+		Eventually(func() int {
+			pool.mu.Lock()
+			defer pool.mu.Unlock()
+			return pool.running
+		}).Should(Equal(1))
+	})
+
+	It("drops forgotten builds", func() {
+		Expect(pool.Submit("a", func(context.Context) error { return nil })).To(Succeed())
+		pool.Forget("a")
+		_, found := pool.Status("a")
+		Expect(found).To(BeFalse())
+	})
+})
+
+func TestIsoBuilder(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IsoBuilder Suite")
+}
@@ -0,0 +1,27 @@
+package isobuilder
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	queueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ibio_iso_build_queue_depth",
+		Help: "Number of image builds waiting for a worker",
+	})
+	queueWaitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ibio_iso_build_queue_wait_seconds",
+		Help:    "Time an image build spent in the queue before a worker picked it up",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	buildDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ibio_iso_build_duration_seconds",
+		Help:    "Time taken to build an image",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(queueDepth, queueWaitSeconds, buildDurationSeconds)
+}
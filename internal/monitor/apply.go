@@ -0,0 +1,19 @@
+package monitor
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ApplyFieldOwner is the field manager of the objects the operator applies to installed clusters
+const ApplyFieldOwner = "image-based-install-operator"
+
+// ApplyManifestFunc applies an object to the cluster c
+type ApplyManifestFunc func(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error
+
+// ApplyManifest applies obj to the cluster c with server-side apply, taking over the fields set by other managers
+func ApplyManifest(ctx context.Context, c client.Client, obj *unstructured.Unstructured) error {
+	return c.Patch(ctx, obj, client.Apply, client.FieldOwner(ApplyFieldOwner), client.ForceOwnership)
+}
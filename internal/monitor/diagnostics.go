@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	DiagnosticsClusterVersionKey   = "clusterversion.yaml"
+	DiagnosticsClusterOperatorsKey = "clusteroperators.yaml"
+	DiagnosticsNodesKey            = "nodes.yaml"
+	DiagnosticsEventsKey           = "events.yaml"
+	DiagnosticsMonitorConfigMapKey = "ibi-monitor-cm.yaml"
+
+	// diagnosticsEventsLimit is the number of most recent Warning events collected
+	diagnosticsEventsLimit = 50
+
+	// diagnosticsEventsPageSize is the number of Warning events listed per request, at most
+	// diagnosticsEventsMaxPages are listed so a cluster flooded with events can't exhaust the spoke client timeout
+	diagnosticsEventsPageSize = 500
+	diagnosticsEventsMaxPages = 10
+)
+
+// CollectDiagnosticsFunc collects diagnostics from the cluster c, keyed by file name
+type CollectDiagnosticsFunc func(ctx context.Context, log logrus.FieldLogger, c client.Client) map[string]string
+
+type clusterVersionDiagnostics struct {
+	Desired    configv1.Release                          `json:"desired"`
+	History    []configv1.UpdateHistory                  `json:"history,omitempty"`
+	Conditions []configv1.ClusterOperatorStatusCondition `json:"conditions,omitempty"`
+}
+
+type clusterOperatorDiagnostics struct {
+	Name       string                                    `json:"name"`
+	Conditions []configv1.ClusterOperatorStatusCondition `json:"conditions,omitempty"`
+}
+
+type nodeDiagnostics struct {
+	Name       string                 `json:"name"`
+	Conditions []corev1.NodeCondition `json:"conditions,omitempty"`
+}
+
+type eventDiagnostics struct {
+	Namespace      string    `json:"namespace,omitempty"`
+	InvolvedObject string    `json:"involvedObject"`
+	Reason         string    `json:"reason,omitempty"`
+	Message        string    `json:"message,omitempty"`
+	Count          int32     `json:"count,omitempty"`
+	LastSeen       time.Time `json:"lastSeen"`
+}
+
+// CollectDiagnostics collects the state of the cluster c that explains why its installation failed: the cluster
+// version, the conditions of the cluster operators and nodes, the recent Warning events and the ibi-monitor-cm
+// ConfigMap. Collection is best-effort, a diagnostic that can't be collected holds the error instead.
+func CollectDiagnostics(ctx context.Context, log logrus.FieldLogger, c client.Client) map[string]string {
+	diagnostics := map[string]string{}
+	for _, collector := range []struct {
+		key     string
+		collect func(context.Context, client.Client) (interface{}, error)
+	}{
+		{DiagnosticsClusterVersionKey, collectClusterVersion},
+		{DiagnosticsClusterOperatorsKey, collectClusterOperators},
+		{DiagnosticsNodesKey, collectNodes},
+		{DiagnosticsEventsKey, collectWarningEvents},
+		{DiagnosticsMonitorConfigMapKey, collectMonitorConfigMap},
+	} {
+		value, err := collector.collect(ctx, c)
+		if err == nil {
+			var content []byte
+			if content, err = yaml.Marshal(value); err == nil {
+				diagnostics[collector.key] = string(content)
+				continue
+			}
+		}
+		log.WithError(err).Warnf("failed to collect %s", collector.key)
+		diagnostics[collector.key] = fmt.Sprintf("failed to collect: %s\n", err)
+	}
+	return diagnostics
+}
+
+func collectClusterVersion(ctx context.Context, c client.Client) (interface{}, error) {
+	cv := &configv1.ClusterVersion{}
+	if err := c.Get(ctx, types.NamespacedName{Name: "version"}, cv); err != nil {
+		return nil, err
+	}
+	return clusterVersionDiagnostics{
+		Desired:    cv.Status.Desired,
+		History:    cv.Status.History,
+		Conditions: cv.Status.Conditions,
+	}, nil
+}
+
+func collectClusterOperators(ctx context.Context, c client.Client) (interface{}, error) {
+	operators := &configv1.ClusterOperatorList{}
+	if err := c.List(ctx, operators); err != nil {
+		return nil, err
+	}
+	diagnostics := []clusterOperatorDiagnostics{}
+	for _, operator := range operators.Items {
+		diagnostics = append(diagnostics, clusterOperatorDiagnostics{Name: operator.Name, Conditions: operator.Status.Conditions})
+	}
+	return diagnostics, nil
+}
+
+func collectNodes(ctx context.Context, c client.Client) (interface{}, error) {
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	diagnostics := []nodeDiagnostics{}
+	for _, node := range nodes.Items {
+		diagnostics = append(diagnostics, nodeDiagnostics{Name: node.Name, Conditions: node.Status.Conditions})
+	}
+	return diagnostics, nil
+}
+
+// collectWarningEvents returns the most recent Warning events of all the namespaces, the latest first. The events
+// are listed in pages, when a page after the first one can't be listed the events of the previous pages are returned.
+func collectWarningEvents(ctx context.Context, c client.Client) (interface{}, error) {
+	diagnostics := []eventDiagnostics{}
+	continueToken := ""
+	for page := 0; page < diagnosticsEventsMaxPages; page++ {
+		events := &corev1.EventList{}
+		if err := c.List(ctx, events,
+			client.MatchingFields{"type": corev1.EventTypeWarning},
+			client.Limit(diagnosticsEventsPageSize),
+			client.Continue(continueToken)); err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			break
+		}
+		for _, event := range events.Items {
+			diagnostics = append(diagnostics, newEventDiagnostics(event))
+		}
+		sort.SliceStable(diagnostics, func(i, j int) bool { return diagnostics[i].LastSeen.After(diagnostics[j].LastSeen) })
+		if len(diagnostics) > diagnosticsEventsLimit {
+			diagnostics = diagnostics[:diagnosticsEventsLimit]
+		}
+
+		continueToken = events.Continue
+		if continueToken == "" {
+			break
+		}
+	}
+	return diagnostics, nil
+}
+
+func newEventDiagnostics(event corev1.Event) eventDiagnostics {
+	involved := event.InvolvedObject
+	name := involved.Name
+	if involved.Namespace != "" {
+		name = fmt.Sprintf("%s/%s", involved.Namespace, involved.Name)
+	}
+	return eventDiagnostics{
+		Namespace:      event.Namespace,
+		InvolvedObject: fmt.Sprintf("%s %s", involved.Kind, name),
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Count:          event.Count,
+		LastSeen:       eventLastSeen(event),
+	}
+}
+
+func eventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+func collectMonitorConfigMap(ctx context.Context, c client.Client) (interface{}, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: IBIOStartTimeCM, Namespace: OcpConfigNamespace}, cm); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"creationTimestamp": cm.CreationTimestamp,
+		"data":              cm.Data,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CollectDiagnostics", func() {
+	var (
+		ctx = context.Background()
+		log = logrus.New()
+		c   client.Client
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		utilruntime.Must(configv1.AddToScheme(scheme))
+		c = fakeclient.NewClientBuilder().
+			WithScheme(scheme).
+			WithIndex(&corev1.Event{}, "type", func(obj client.Object) []string {
+				return []string{obj.(*corev1.Event).Type}
+			}).
+			Build()
+	})
+
+	It("collects the state of the cluster", func() {
+		Expect(c.Create(ctx, &configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: "2df3ed12-a142-437d-a398-c551dfd8e9ba"},
+			Status: configv1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.16.0"},
+				History: []configv1.UpdateHistory{{State: configv1.PartialUpdate, Version: "4.16.0"}},
+				Conditions: []configv1.ClusterOperatorStatusCondition{{
+					Type:    configv1.OperatorProgressing,
+					Status:  configv1.ConditionTrue,
+					Message: "Working towards 4.16.0",
+				}},
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, &configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: "etcd"},
+			Status: configv1.ClusterOperatorStatus{
+				Conditions: []configv1.ClusterOperatorStatusCondition{{
+					Type:    configv1.OperatorDegraded,
+					Status:  configv1.ConditionTrue,
+					Message: "etcd member is unhealthy",
+				}},
+			},
+		})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"}},
+			},
+		})).To(Succeed())
+		now := time.Now()
+		for i := 0; i < diagnosticsEventsLimit+5; i++ {
+			Expect(c.Create(ctx, &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: fmt.Sprintf("warning-%d", i), Namespace: "openshift-etcd"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "openshift-etcd", Name: "etcd-node1"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				Message:        fmt.Sprintf("Back-off restarting failed container %d", i),
+				LastTimestamp:  metav1.NewTime(now.Add(time.Duration(i) * time.Minute)),
+			})).To(Succeed())
+		}
+		Expect(c.Create(ctx, &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{Name: "normal", Namespace: "openshift-etcd"},
+			Type:       corev1.EventTypeNormal,
+			Reason:     "Pulled",
+		})).To(Succeed())
+		Expect(c.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: IBIOStartTimeCM, Namespace: OcpConfigNamespace},
+			Data:       map[string]string{"reconfigured": "true"},
+		})).To(Succeed())
+
+		diagnostics := CollectDiagnostics(ctx, log, c)
+		Expect(diagnostics).To(HaveLen(5))
+		Expect(diagnostics[DiagnosticsClusterVersionKey]).To(ContainSubstring("Working towards 4.16.0"))
+		Expect(diagnostics[DiagnosticsClusterVersionKey]).To(ContainSubstring("state: Partial"))
+		Expect(diagnostics[DiagnosticsClusterOperatorsKey]).To(ContainSubstring("etcd member is unhealthy"))
+		Expect(diagnostics[DiagnosticsNodesKey]).To(ContainSubstring("KubeletNotReady"))
+		Expect(diagnostics[DiagnosticsEventsKey]).To(ContainSubstring("involvedObject: Pod openshift-etcd/etcd-node1"))
+		Expect(diagnostics[DiagnosticsEventsKey]).To(ContainSubstring(fmt.Sprintf("container %d", diagnosticsEventsLimit+4)))
+		Expect(diagnostics[DiagnosticsEventsKey]).NotTo(ContainSubstring("container 4\n"))
+		Expect(diagnostics[DiagnosticsEventsKey]).NotTo(ContainSubstring("Pulled"))
+		Expect(diagnostics[DiagnosticsMonitorConfigMapKey]).To(ContainSubstring("reconfigured: \"true\""))
+	})
+
+	It("lists the Warning events in pages and keeps the ones listed before a failure", func() {
+		warning := func(name string, lastSeen time.Time) corev1.Event {
+			return corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "openshift-etcd"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "openshift-etcd", Name: name},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "BackOff",
+				LastTimestamp:  metav1.NewTime(lastSeen),
+			}
+		}
+		now := time.Now()
+		c = fakeclient.NewClientBuilder().
+			WithInterceptorFuncs(interceptor.Funcs{
+				List: func(_ context.Context, _ client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					listOpts := &client.ListOptions{}
+					listOpts.ApplyOptions(opts)
+					Expect(listOpts.Limit).To(BeEquivalentTo(diagnosticsEventsPageSize))
+					events := list.(*corev1.EventList)
+					switch listOpts.Continue {
+					case "":
+						events.Items = []corev1.Event{warning("older", now.Add(-time.Hour))}
+						events.Continue = "second"
+					case "second":
+						events.Items = []corev1.Event{warning("newer", now)}
+						events.Continue = "third"
+					default:
+						return errors.New("context deadline exceeded")
+					}
+					return nil
+				},
+			}).
+			Build()
+
+		value, err := collectWarningEvents(ctx, c)
+		Expect(err).NotTo(HaveOccurred())
+		events := value.([]eventDiagnostics)
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].InvolvedObject).To(Equal("Pod openshift-etcd/newer"))
+		Expect(events[1].InvolvedObject).To(Equal("Pod openshift-etcd/older"))
+	})
+
+	It("records the diagnostics that fail to be collected", func() {
+		diagnostics := CollectDiagnostics(ctx, log, c)
+		Expect(diagnostics[DiagnosticsClusterVersionKey]).To(HavePrefix("failed to collect:"))
+		Expect(diagnostics[DiagnosticsMonitorConfigMapKey]).To(HavePrefix("failed to collect:"))
+		Expect(diagnostics[DiagnosticsNodesKey]).To(Equal("[]\n"))
+	})
+})
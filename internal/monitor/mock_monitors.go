@@ -2,14 +2,19 @@ package monitor
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
 )
 
 func SuccessMonitor(_ context.Context, _ logrus.FieldLogger, _ client.Client) ClusterInstallStatus {
 	return ClusterInstallStatus{
 		Installed:            true,
+		APIReachable:         true,
 		ClusterVersionStatus: "ClusterVersion is available",
 		NodesStatus:          "All nodes are ready",
 	}
@@ -20,9 +25,56 @@ var _ GetInstallStatusFunc = SuccessMonitor
 func FailureMonitor(_ context.Context, _ logrus.FieldLogger, _ client.Client) ClusterInstallStatus {
 	return ClusterInstallStatus{
 		Installed:            false,
+		APIReachable:         true,
 		ClusterVersionStatus: "Cluster version is not available",
 		NodesStatus:          "Node test is NotReady",
 	}
 }
 
 var _ GetInstallStatusFunc = FailureMonitor
+
+func UnreachableMonitor(_ context.Context, _ logrus.FieldLogger, _ client.Client) ClusterInstallStatus {
+	return ClusterInstallStatus{
+		Installed:            false,
+		ClusterVersionStatus: "Failed to get ibi-monitor-cm : connection refused",
+	}
+}
+
+var _ GetInstallStatusFunc = UnreachableMonitor
+
+func PassingReadinessGates(_ context.Context, _ logrus.FieldLogger, _ client.Client, _ []v1alpha1.ReadinessGate) []string {
+	return nil
+}
+
+var _ GetReadinessGatesStatusFunc = PassingReadinessGates
+
+func PendingReadinessGates(_ context.Context, _ logrus.FieldLogger, _ client.Client, gates []v1alpha1.ReadinessGate) []string {
+	pending := make([]string, 0, len(gates))
+	for _, gate := range gates {
+		pending = append(pending, fmt.Sprintf("%s: not found", readinessGateName(gate)))
+	}
+	return pending
+}
+
+var _ GetReadinessGatesStatusFunc = PendingReadinessGates
+
+func AppliedManifest(_ context.Context, _ client.Client, _ *unstructured.Unstructured) error {
+	return nil
+}
+
+var _ ApplyManifestFunc = AppliedManifest
+
+func FailedManifest(_ context.Context, _ client.Client, obj *unstructured.Unstructured) error {
+	return fmt.Errorf("no matches for kind %q in version %q", obj.GetKind(), obj.GetAPIVersion())
+}
+
+var _ ApplyManifestFunc = FailedManifest
+
+func StaticDiagnostics(_ context.Context, _ logrus.FieldLogger, _ client.Client) map[string]string {
+	return map[string]string{
+		DiagnosticsClusterVersionKey: "conditions:\n- message: Working towards 4.16.0\n",
+		DiagnosticsNodesKey:          "- name: node1\n",
+	}
+}
+
+var _ CollectDiagnosticsFunc = StaticDiagnostics
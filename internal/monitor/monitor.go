@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +10,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,9 +25,13 @@ const (
 )
 
 type ClusterInstallStatus struct {
-	Installed            bool
+	Installed bool
+	// APIReachable is true when the API of the cluster answered, even with an error
+	APIReachable         bool
 	ClusterVersionStatus string
 	NodesStatus          string
+	// ReadinessGatesStatus describes the pending readiness gates, empty when the installation has none pending
+	ReadinessGatesStatus string
 }
 
 func (status *ClusterInstallStatus) String() string {
@@ -33,7 +39,11 @@ func (status *ClusterInstallStatus) String() string {
 	if status.Installed {
 		installStatus = "installed"
 	}
-	return fmt.Sprintf("Cluster is %s\nClusterVersion Status: %s\nNodes Status: %s", installStatus, status.ClusterVersionStatus, status.NodesStatus)
+	message := fmt.Sprintf("Cluster is %s\nClusterVersion Status: %s\nNodes Status: %s", installStatus, status.ClusterVersionStatus, status.NodesStatus)
+	if status.ReadinessGatesStatus != "" {
+		message += fmt.Sprintf("\nReadiness Gates Status: %s", status.ReadinessGatesStatus)
+	}
+	return message
 }
 
 type GetInstallStatusFunc func(ctx context.Context, log logrus.FieldLogger, c client.Client) ClusterInstallStatus
@@ -52,6 +62,7 @@ func GetClusterInstallStatus(ctx context.Context, log logrus.FieldLogger, c clie
 	if err != nil {
 		return ClusterInstallStatus{
 			Installed:            false,
+			APIReachable:         apiAnswered(err),
 			ClusterVersionStatus: fmt.Sprintf("Failed to get %s : %s", IBIOStartTimeCM, err),
 		}
 	}
@@ -60,6 +71,7 @@ func GetClusterInstallStatus(ctx context.Context, log logrus.FieldLogger, c clie
 		cvMessage = fmt.Sprintf("Failed to check cluster version status: %s", err)
 		return ClusterInstallStatus{
 			Installed:            false,
+			APIReachable:         true,
 			ClusterVersionStatus: cvMessage,
 		}
 	}
@@ -71,11 +83,18 @@ func GetClusterInstallStatus(ctx context.Context, log logrus.FieldLogger, c clie
 
 	return ClusterInstallStatus{
 		Installed:            cvAvailable && nodesReady,
+		APIReachable:         true,
 		ClusterVersionStatus: cvMessage,
 		NodesStatus:          nodesMessage,
 	}
 }
 
+// apiAnswered returns true when err is a response of the API server rather than a failure to reach it
+func apiAnswered(err error) bool {
+	var status k8sapierrors.APIStatus
+	return errors.As(err, &status)
+}
+
 func clusterVersionStatus(ctx context.Context, log logrus.FieldLogger, c client.Client, reconfigurationStartTime metav1.Time) (bool, string, error) {
 	cv := &configv1.ClusterVersion{}
 	if err := c.Get(ctx, types.NamespacedName{Name: "version"}, cv); err != nil {
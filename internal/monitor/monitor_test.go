@@ -137,6 +137,7 @@ var _ = Describe("GetClusterInstallStatus", func() {
 
 		status := GetClusterInstallStatus(ctx, log, c)
 		Expect(status.Installed).To(BeFalse())
+		Expect(status.APIReachable).To(BeTrue())
 		Expect(status.ClusterVersionStatus).To(ContainSubstring("Failed to get"))
 	})
 
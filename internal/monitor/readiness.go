@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+const defaultReadinessGateConditionStatus = "True"
+
+// GetReadinessGatesStatusFunc returns the readiness gates that don't pass and why
+type GetReadinessGatesStatusFunc func(ctx context.Context, log logrus.FieldLogger, c client.Client, gates []v1alpha1.ReadinessGate) []string
+
+// GetReadinessGatesStatus evaluates the readiness gates on the cluster c, it returns a message for each gate that
+// doesn't pass
+func GetReadinessGatesStatus(ctx context.Context, log logrus.FieldLogger, c client.Client, gates []v1alpha1.ReadinessGate) []string {
+	var pending []string
+	for _, gate := range gates {
+		if reason := checkReadinessGate(ctx, c, gate); reason != "" {
+			message := fmt.Sprintf("%s: %s", readinessGateName(gate), reason)
+			log.Info(message)
+			pending = append(pending, message)
+		}
+	}
+	return pending
+}
+
+func readinessGateName(gate v1alpha1.ReadinessGate) string {
+	if gate.Namespace == "" {
+		return fmt.Sprintf("%s %s", gate.Kind, gate.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", gate.Kind, gate.Namespace, gate.Name)
+}
+
+// checkReadinessGate returns why the gate doesn't pass, an empty string when it does
+func checkReadinessGate(ctx context.Context, c client.Client, gate v1alpha1.ReadinessGate) string {
+	gv, err := schema.ParseGroupVersion(gate.APIVersion)
+	if err != nil {
+		return fmt.Sprintf("invalid apiVersion %s: %s", gate.APIVersion, err)
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gv.WithKind(gate.Kind))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: gate.Namespace, Name: gate.Name}, obj); err != nil {
+		if k8sapierrors.IsNotFound(err) {
+			return "not found"
+		}
+		return fmt.Sprintf("failed to get: %s", err)
+	}
+
+	if gate.ConditionType != "" {
+		return checkReadinessGateCondition(obj, gate)
+	}
+	value, err := evaluateJSONPath(obj, gate.JSONPath)
+	if err != nil {
+		return fmt.Sprintf("failed to evaluate %s: %s", gate.JSONPath, err)
+	}
+	if value != gate.Value {
+		return fmt.Sprintf("%s is %q, expected %q", gate.JSONPath, value, gate.Value)
+	}
+	return ""
+}
+
+func checkReadinessGateCondition(obj *unstructured.Unstructured, gate v1alpha1.ReadinessGate) string {
+	expected := gate.ConditionStatus
+	if expected == "" {
+		expected = defaultReadinessGateConditionStatus
+	}
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return fmt.Sprintf("invalid status conditions: %s", err)
+	}
+	for _, raw := range conditions {
+		cond, ok := raw.(map[string]interface{})
+		if !ok || cond["type"] != gate.ConditionType {
+			continue
+		}
+		status, _ := cond["status"].(string)
+		if status != expected {
+			return fmt.Sprintf("condition %s is %s, expected %s", gate.ConditionType, status, expected)
+		}
+		return ""
+	}
+	return fmt.Sprintf("condition %s not found", gate.ConditionType)
+}
+
+// evaluateJSONPath returns the result of a JSONPath expression on obj, the braces around the expression are optional
+func evaluateJSONPath(obj *unstructured.Unstructured, expression string) (string, error) {
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	j := jsonpath.New("readinessGate")
+	if err := j.Parse(expression); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := j.Execute(&buf, obj.Object); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
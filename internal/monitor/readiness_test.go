@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/image-based-install-operator/api/v1alpha1"
+)
+
+var _ = Describe("GetReadinessGatesStatus", func() {
+	var (
+		ctx = context.Background()
+		log = logrus.New()
+		c   client.Client
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		utilruntime.Must(corev1.AddToScheme(scheme))
+		c = fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		Expect(c.Create(ctx, node)).To(Succeed())
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "sync", Namespace: "openshift-sriov-network-operator"},
+			Data:       map[string]string{"syncStatus": "InProgress"},
+		}
+		Expect(c.Create(ctx, cm)).To(Succeed())
+	})
+
+	It("passes the gates whose condition or JSONPath match", func() {
+		gates := []v1alpha1.ReadinessGate{{
+			APIVersion:    "v1",
+			Kind:          "Node",
+			Name:          "node1",
+			ConditionType: "Ready",
+		}, {
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "openshift-sriov-network-operator",
+			Name:       "sync",
+			JSONPath:   ".data.syncStatus",
+			Value:      "InProgress",
+		}}
+		Expect(GetReadinessGatesStatus(ctx, log, c, gates)).To(BeEmpty())
+	})
+
+	It("lists the pending gates and why", func() {
+		gates := []v1alpha1.ReadinessGate{{
+			APIVersion:      "v1",
+			Kind:            "Node",
+			Name:            "node1",
+			ConditionType:   "Ready",
+			ConditionStatus: "False",
+		}, {
+			APIVersion:    "v1",
+			Kind:          "Node",
+			Name:          "node1",
+			ConditionType: "NetworkUnavailable",
+		}, {
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "openshift-sriov-network-operator",
+			Name:       "sync",
+			JSONPath:   "{.data.syncStatus}",
+			Value:      "Succeeded",
+		}, {
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "openshift-ptp",
+			Name:       "missing",
+			JSONPath:   "{.data.state}",
+		}}
+		Expect(GetReadinessGatesStatus(ctx, log, c, gates)).To(Equal([]string{
+			"Node node1: condition Ready is True, expected False",
+			"Node node1: condition NetworkUnavailable not found",
+			`ConfigMap openshift-sriov-network-operator/sync: {.data.syncStatus} is "InProgress", expected "Succeeded"`,
+			"ConfigMap openshift-ptp/missing: not found",
+		}))
+	})
+})